@@ -0,0 +1,288 @@
+package cdnetworks
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/acme"
+)
+
+const (
+	acmeChallengeDns01  = "dns-01"
+	acmeChallengeHttp01 = "http-01"
+
+	// acmeCleanUpTimeout bounds cleaning up a challenge, which is done even if
+	// the issuance times out.
+	acmeCleanUpTimeout = 5 * time.Minute
+)
+
+// acmeChallenge is an ACME challenge of a domain to be solved by
+// acmeChallengeHook.
+type acmeChallenge struct {
+	// Type is either dns-01 or http-01.
+	Type string
+	// Domain is the domain being validated, without the leading "*." of a
+	// wildcard name.
+	Domain           string
+	Token            string
+	KeyAuthorization string
+	// RecordName and RecordValue are the TXT record of dns-01.
+	RecordName  string
+	RecordValue string
+	// Path is the URL path of http-01, which must respond KeyAuthorization.
+	Path string
+}
+
+// acmeChallengeHook makes the ACME challenges reachable by the CA, e.g. by
+// creating the DNS TXT record through the DNS provider of the team.
+type acmeChallengeHook interface {
+	// Present makes the challenge response reachable, and returns after it is
+	// propagated.
+	Present(ctx context.Context, challenge *acmeChallenge) error
+	// CleanUp removes what is made by Present, it is called even if the
+	// challenge fails.
+	CleanUp(ctx context.Context, challenge *acmeChallenge) error
+}
+
+// commandChallengeHook runs the commands with the challenge passed in the
+// environment variables, so that any DNS provider or web server can be wired
+// by a script.
+type commandChallengeHook struct {
+	presentCommand  []string
+	cleanUpCommand  []string
+	propagationWait time.Duration
+}
+
+func (h *commandChallengeHook) Present(ctx context.Context, challenge *acmeChallenge) error {
+	if err := runChallengeCommand(ctx, h.presentCommand, challenge); err != nil {
+		return err
+	}
+	select {
+	case <-time.After(h.propagationWait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *commandChallengeHook) CleanUp(ctx context.Context, challenge *acmeChallenge) error {
+	if len(h.cleanUpCommand) == 0 {
+		return nil
+	}
+	return runChallengeCommand(ctx, h.cleanUpCommand, challenge)
+}
+
+func runChallengeCommand(ctx context.Context, command []string, challenge *acmeChallenge) error {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = append(os.Environ(),
+		"ACME_CHALLENGE_TYPE="+challenge.Type,
+		"ACME_DOMAIN="+challenge.Domain,
+		"ACME_TOKEN="+challenge.Token,
+		"ACME_KEY_AUTHORIZATION="+challenge.KeyAuthorization,
+		"ACME_DNS_RECORD_NAME="+challenge.RecordName,
+		"ACME_DNS_RECORD_VALUE="+challenge.RecordValue,
+		"ACME_HTTP_PATH="+challenge.Path,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("command %q fails: %w: %s", strings.Join(command, " "), err, bytes.TrimSpace(output))
+	}
+	return nil
+}
+
+// acmeIssuer obtains certificates from an ACME directory, solving the
+// challenges of challengeType by hook.
+type acmeIssuer struct {
+	client        *acme.Client
+	contact       []string
+	challengeType string
+	hook          acmeChallengeHook
+}
+
+// newAcmeIssuer creates the issuer of the account accountKey. caPem are the
+// extra CA certificates trusted for directoryUrl, e.g. the root of a local
+// test CA, empty to trust the system roots only.
+func newAcmeIssuer(directoryUrl, caPem string, accountKey crypto.Signer, email, challengeType string, hook acmeChallengeHook) (*acmeIssuer, error) {
+	httpClient := http.DefaultClient
+	if caPem != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(caPem)) {
+			return nil, errors.New("no PEM encoded CA certificate is found")
+		}
+		httpClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{RootCAs: pool},
+			},
+		}
+	}
+
+	issuer := &acmeIssuer{
+		client: &acme.Client{
+			Key:          accountKey,
+			DirectoryURL: directoryUrl,
+			HTTPClient:   httpClient,
+			UserAgent:    "terraform-provider-st-cdnetworks",
+		},
+		challengeType: challengeType,
+		hook:          hook,
+	}
+	if email != "" {
+		issuer.contact = []string{"mailto:" + email}
+	}
+	return issuer, nil
+}
+
+// issue returns the DER encoded certificate chain of domains signed for
+// privateKey, the leaf certificate first. The account is registered if it
+// does not exist, the terms of service are accepted.
+func (i *acmeIssuer) issue(ctx context.Context, domains []string, privateKey crypto.Signer) ([][]byte, error) {
+	_, err := i.client.Register(ctx, &acme.Account{Contact: i.contact}, acme.AcceptTOS)
+	if err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return nil, fmt.Errorf("fail to register account: %w", err)
+	}
+
+	order, err := i.client.AuthorizeOrder(ctx, acme.DomainIDs(domains...))
+	if err != nil {
+		return nil, fmt.Errorf("fail to create order: %w", err)
+	}
+	for _, authzUrl := range order.AuthzURLs {
+		if err = i.authorize(ctx, authzUrl); err != nil {
+			return nil, err
+		}
+	}
+	if _, err = i.client.WaitOrder(ctx, order.URI); err != nil {
+		return nil, fmt.Errorf("fail to wait for order: %w", err)
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create CSR: %w", err)
+	}
+	chain, _, err := i.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		// The CA may issue the certificate asynchronously without responding
+		// the order URL to be waited for, wait for the order created instead.
+		finalized, waitErr := i.client.WaitOrder(ctx, order.URI)
+		if waitErr != nil || finalized.CertURL == "" {
+			return nil, fmt.Errorf("fail to finalize order: %w", err)
+		}
+		if chain, err = i.client.FetchCert(ctx, finalized.CertURL, true); err != nil {
+			return nil, fmt.Errorf("fail to fetch certificate: %w", err)
+		}
+	}
+	return chain, nil
+}
+
+// authorize solves the challenge of the authorization at authzUrl, unless it
+// is valid already.
+func (i *acmeIssuer) authorize(ctx context.Context, authzUrl string) error {
+	authz, err := i.client.GetAuthorization(ctx, authzUrl)
+	if err != nil {
+		return fmt.Errorf("fail to get authorization: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	domain := authz.Identifier.Value
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == i.challengeType {
+			challenge = c
+			break
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("%s challenge is not offered for %s", i.challengeType, domain)
+	}
+
+	c := &acmeChallenge{
+		Type:   i.challengeType,
+		Domain: domain,
+		Token:  challenge.Token,
+	}
+	if c.KeyAuthorization, err = i.client.HTTP01ChallengeResponse(challenge.Token); err != nil {
+		return err
+	}
+	switch i.challengeType {
+	case acmeChallengeDns01:
+		c.RecordName = "_acme-challenge." + domain
+		if c.RecordValue, err = i.client.DNS01ChallengeRecord(challenge.Token); err != nil {
+			return err
+		}
+	case acmeChallengeHttp01:
+		c.Path = i.client.HTTP01ChallengePath(challenge.Token)
+	}
+
+	defer func() {
+		cleanUpCtx, cancel := context.WithTimeout(context.Background(), acmeCleanUpTimeout)
+		defer cancel()
+		if err := i.hook.CleanUp(cleanUpCtx, c); err != nil {
+			tflog.Warn(ctx, "Fail to clean up ACME challenge", map[string]interface{}{
+				"domain": domain,
+				"error":  err.Error(),
+			})
+		}
+	}()
+	if err = i.hook.Present(ctx, c); err != nil {
+		return fmt.Errorf("fail to present %s challenge for %s: %w", i.challengeType, domain, err)
+	}
+	if _, err = i.client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("fail to accept %s challenge for %s: %w", i.challengeType, domain, err)
+	}
+	if _, err = i.client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("fail to authorize %s: %w", domain, err)
+	}
+	return nil
+}
+
+// generatePrivateKey generates the private key of algorithm, one of the
+// csrAlgorithm constants.
+func generatePrivateKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case csrAlgorithmRsa2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case csrAlgorithmRsa4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case csrAlgorithmEcdsa256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case csrAlgorithmEcdsa384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+}
+
+// encodePrivateKey encodes the private key in PKCS #8, which is parsed by
+// parsePemPrivateKey.
+func encodePrivateKey(key crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
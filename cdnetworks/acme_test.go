@@ -0,0 +1,174 @@
+package cdnetworks
+
+import (
+	"context"
+	"crypto/x509"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// noopChallengeHook solves nothing, for the test CA validating every
+// challenge, e.g. Pebble run with PEBBLE_VA_ALWAYS_VALID=1.
+type noopChallengeHook struct{}
+
+func (noopChallengeHook) Present(context.Context, *acmeChallenge) error { return nil }
+
+func (noopChallengeHook) CleanUp(context.Context, *acmeChallenge) error { return nil }
+
+// TestAcmeIssuerIssue issues a certificate from Pebble. It is skipped unless
+// ACME_PEBBLE_DIRECTORY is set to the directory URL of Pebble, e.g.
+// https://localhost:14000/dir, run with PEBBLE_VA_ALWAYS_VALID=1.
+// ACME_PEBBLE_CA_FILE is the PEM file of the CA certificate of the Pebble
+// listener, e.g. test/certs/pebble.minica.pem in the Pebble repository.
+func TestAcmeIssuerIssue(t *testing.T) {
+	directoryUrl := os.Getenv("ACME_PEBBLE_DIRECTORY")
+	if directoryUrl == "" {
+		t.Skip("ACME_PEBBLE_DIRECTORY is not set")
+	}
+	var caPem string
+	if caFile := os.Getenv("ACME_PEBBLE_CA_FILE"); caFile != "" {
+		b, err := os.ReadFile(caFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		caPem = string(b)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	for _, challengeType := range []string{acmeChallengeDns01, acmeChallengeHttp01} {
+		t.Run(challengeType, func(t *testing.T) {
+			accountKey, err := generatePrivateKey(csrAlgorithmEcdsa256)
+			if err != nil {
+				t.Fatal(err)
+			}
+			issuer, err := newAcmeIssuer(directoryUrl, caPem, accountKey, "test@example.com", challengeType, noopChallengeHook{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			privateKey, err := generatePrivateKey(csrAlgorithmRsa2048)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			domains := []string{"www.example.com", "example.com"}
+			chain, err := issuer.issue(ctx, domains, privateKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(chain) < 2 {
+				t.Fatalf("expect the certificate with its issuers, got %d certificates", len(chain))
+			}
+			leaf, err := x509.ParseCertificate(chain[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, domain := range domains {
+				if err = leaf.VerifyHostname(domain); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+// memoryPrivateState is the private state kept in memory.
+type memoryPrivateState map[string][]byte
+
+func (s memoryPrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return s[key], nil
+}
+
+func (s memoryPrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	s[key] = value
+	return nil
+}
+
+// TestAcmeCertificateReuseIssued checks that the certificate recorded by a
+// failed renewal is reused only for the same directory, domains and key type,
+// until the record is cleared by a successful upload.
+func TestAcmeCertificateReuseIssued(t *testing.T) {
+	ctx := context.Background()
+	newModel := func() *acmeCertificateModel {
+		return &acmeCertificateModel{
+			DirectoryUrl:            types.StringValue("https://localhost:14000/dir"),
+			CommonName:              types.StringValue("www.example.com"),
+			SubjectAlternativeNames: types.ListNull(types.StringType),
+			KeyType:                 types.StringValue(csrAlgorithmEcdsa256),
+			RenewBeforeDays:         types.Int64Value(defaultAcmeRenewBeforeDays),
+			CertificatePem:          types.StringUnknown(),
+			IssuerPem:               types.StringUnknown(),
+			PrivateKeyPem:           types.StringUnknown(),
+			NotBefore:               types.StringUnknown(),
+			NotAfter:                types.StringUnknown(),
+		}
+	}
+
+	issued := newModel()
+	issued.CertificatePem = types.StringValue("certificate")
+	issued.IssuerPem = types.StringValue("issuer")
+	issued.PrivateKeyPem = types.StringValue("key")
+	issued.NotBefore = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	issued.NotAfter = types.StringValue(time.Now().AddDate(0, 0, 90).UTC().Format(time.RFC3339))
+	private := memoryPrivateState{}
+	if diags := issued.recordIssued(ctx, private); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(m *acmeCertificateModel)
+		reused bool
+	}{
+		{name: "same", modify: func(m *acmeCertificateModel) {}, reused: true},
+		{name: "common name changed", modify: func(m *acmeCertificateModel) {
+			m.CommonName = types.StringValue("api.example.com")
+		}},
+		{name: "key type changed", modify: func(m *acmeCertificateModel) {
+			m.KeyType = types.StringValue(csrAlgorithmRsa2048)
+		}},
+		{name: "directory changed", modify: func(m *acmeCertificateModel) {
+			m.DirectoryUrl = types.StringValue("https://acme-v02.api.letsencrypt.org/directory")
+		}},
+		{name: "expiring", modify: func(m *acmeCertificateModel) {
+			m.RenewBeforeDays = types.Int64Value(100)
+		}},
+		{name: "unknown domains", modify: func(m *acmeCertificateModel) {
+			m.CommonName = types.StringUnknown()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newModel()
+			tt.modify(m)
+			reused, diags := m.reuseIssued(ctx, private)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if reused != tt.reused {
+				t.Fatalf("reused = %v, expect %v", reused, tt.reused)
+			}
+			if reused && !m.CertificatePem.Equal(issued.CertificatePem) {
+				t.Fatalf("certificate_pem = %s, expect %s", m.CertificatePem, issued.CertificatePem)
+			}
+			if !reused && !m.CertificatePem.IsUnknown() {
+				t.Fatalf("certificate_pem = %s, expect unknown", m.CertificatePem)
+			}
+		})
+	}
+
+	reused, diags := newModel().reuseIssued(ctx, memoryPrivateState{})
+	if diags.HasError() || reused {
+		t.Fatalf("reused = %v without recorded certificate, diags: %v", reused, diags)
+	}
+	cleared := memoryPrivateState{privateStateKeyIssued: []byte("{}")}
+	reused, diags = newModel().reuseIssued(ctx, cleared)
+	if diags.HasError() || reused {
+		t.Fatalf("reused = %v with the record cleared, diags: %v", reused, diags)
+	}
+}
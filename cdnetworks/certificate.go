@@ -0,0 +1,429 @@
+package cdnetworks
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/md5"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+const (
+	// defaultExpiryWarningDays is the default of expiry_warning_days of the
+	// provider.
+	defaultExpiryWarningDays = 30
+	// certificateNotFoundCode is the code of QueryCertificateInfo if the
+	// certificate does not exist.
+	certificateNotFoundCode = 19638021
+)
+
+var certificateDomainAttributeTypes = map[string]attr.Type{
+	"domain_id":   types.StringType,
+	"domain_name": types.StringType,
+}
+
+// certificateTimeLayouts are the layouts of the validity time returned by
+// vendor.
+var certificateTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// certificateTimeValue converts the validity time returned by vendor to
+// RFC3339 in UTC, keeps the original value if it can not be parsed.
+func certificateTimeValue(s *string) types.String {
+	if s == nil || *s == "" {
+		return types.StringNull()
+	}
+	if t, ok := parseCertificateTime(*s); ok {
+		return types.StringValue(t.UTC().Format(time.RFC3339))
+	}
+	return types.StringValue(*s)
+}
+
+func parseCertificateTime(s string) (time.Time, bool) {
+	for _, layout := range certificateTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// checkCertificateExpiry warns if the certificate expires within warningDays.
+// An expired certificate is reported as an error if failOnExpired, otherwise
+// a warning.
+func checkCertificateExpiry(p path.Path, name string, notAfter time.Time, warningDays int64, failOnExpired bool, now time.Time, diags *diag.Diagnostics) {
+	expiry := notAfter.UTC().Format(time.RFC3339)
+	switch {
+	case !now.Before(notAfter):
+		summary := "Certificate expired"
+		detail := fmt.Sprintf("The certificate %q expired at %s.", name, expiry)
+		if failOnExpired {
+			diags.AddAttributeError(p, summary, detail)
+		} else {
+			diags.AddAttributeWarning(p, summary, detail)
+		}
+	case now.AddDate(0, 0, int(warningDays)).After(notAfter):
+		diags.AddAttributeWarning(p, "Certificate expiring",
+			fmt.Sprintf("The certificate %q expires at %s, in %d days.", name, expiry, int(notAfter.Sub(now).Hours()/24)))
+	}
+}
+
+// certificateDomainsValue converts the domains using the certificate to a list
+// of domain_id and domain_name objects.
+func certificateDomainsValue(domains []*cdnetworksapi.CertificateDomain) (types.List, diag.Diagnostics) {
+	elements := make([]attr.Value, 0, len(domains))
+	for _, domain := range domains {
+		elements = append(elements, types.ObjectValueMust(certificateDomainAttributeTypes, map[string]attr.Value{
+			"domain_id":   types.StringPointerValue(domain.DomainId),
+			"domain_name": types.StringPointerValue(domain.DomainName),
+		}))
+	}
+	return types.ListValue(types.ObjectType{AttrTypes: certificateDomainAttributeTypes}, elements)
+}
+
+// formatCertificateDomains formats the domains for diagnostics, e.g.
+// "www.example.com (12345), img.example.com (12346)".
+func formatCertificateDomains(domains []*cdnetworksapi.CertificateDomain) string {
+	formatted := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		var domainId, domainName string
+		if domain.DomainId != nil {
+			domainId = *domain.DomainId
+		}
+		if domain.DomainName != nil {
+			domainName = *domain.DomainName
+		}
+		formatted = append(formatted, fmt.Sprintf("%s (%s)", domainName, domainId))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// stringsValue converts the strings returned by vendor to a list, empty
+// strings are skipped.
+func stringsValue(ctx context.Context, values []string) (types.List, diag.Diagnostics) {
+	elements := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			elements = append(elements, value)
+		}
+	}
+	return types.ListValueFrom(ctx, types.StringType, elements)
+}
+
+// md5Value converts the MD5 fingerprint returned by vendor to lower case,
+// null if it is empty.
+func md5Value(md5 *string) types.String {
+	if md5 == nil || *md5 == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(strings.ToLower(*md5))
+}
+
+// decodePemBlocks decodes all the PEM blocks of s, the content between the
+// blocks is ignored.
+func decodePemBlocks(s string) []*pem.Block {
+	blocks := make([]*pem.Block, 0)
+	rest := []byte(s)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return blocks
+		}
+		blocks = append(blocks, block)
+	}
+}
+
+// certificateBundle is the certificate chain parsed from the configuration,
+// ordered from the leaf certificate to the root.
+type certificateBundle struct {
+	chain []parsedCertificate
+}
+
+// pem encodes the chain in the order expected by vendor, the leaf certificate
+// first and followed by the CA certificates.
+func (b *certificateBundle) pem() string {
+	return encodeCertificates(b.chain)
+}
+
+// leafPem encodes the leaf certificate only.
+func (b *certificateBundle) leafPem() string {
+	return encodeCertificates(b.chain[:1])
+}
+
+// chainPem encodes the CA certificates only, empty if there is none.
+func (b *certificateBundle) chainPem() string {
+	return encodeCertificates(b.chain[1:])
+}
+
+// certificateFingerprints are the MD5 fingerprints of the certificate, the CA
+// chain and the private key, in the same form as crt-md5, ca-md5 and key-md5
+// of QueryCertificate.
+type certificateFingerprints struct {
+	crtMd5 string
+	caMd5  string
+	keyMd5 string
+}
+
+// fingerprints computes the fingerprints of the bundle and the PEM private
+// key sslKey as uploaded. caMd5 is empty if there is no CA certificate, and
+// keyMd5 is empty if sslKey is, i.e. the private key is kept by vendor.
+func (b *certificateBundle) fingerprints(sslKey string) certificateFingerprints {
+	fingerprints := certificateFingerprints{
+		crtMd5: md5Hex([]byte(b.leafPem())),
+	}
+	if chain := b.chainPem(); chain != "" {
+		fingerprints.caMd5 = md5Hex([]byte(chain))
+	}
+	if blocks := decodePemBlocks(sslKey); len(blocks) > 0 {
+		fingerprints.keyMd5 = md5Hex(pem.EncodeToMemory(blocks[0]))
+	}
+	return fingerprints
+}
+
+func md5Hex(b []byte) string {
+	sum := md5.Sum(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func encodeCertificates(certs []parsedCertificate) string {
+	var buf bytes.Buffer
+	for _, cert := range certs {
+		pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.cert.Raw})
+	}
+	return buf.String()
+}
+
+// parsedCertificate is a certificate with the attribute it comes from.
+type parsedCertificate struct {
+	cert *x509.Certificate
+	path path.Path
+}
+
+// parseCertificateBundle parses the certificates of sslCertificate and
+// certificateChain, finds the leaf certificate by the private key, and orders
+// the CA certificates by issuer. sslKey is empty if the private key is kept by
+// vendor, then the leaf certificate is the one issuing none of the others. The
+// problems are reported as diagnostics of the attributes at certificatePath,
+// chainPath and keyPath. The validity of the certificates is not checked, see
+// checkCertificateExpiry.
+func parseCertificateBundle(sslCertificate, certificateChain, sslKey string, certificatePath, chainPath, keyPath path.Path) (*certificateBundle, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	certs := parsePemCertificates(sslCertificate, certificatePath, &diags)
+	if len(certs) == 0 && !diags.HasError() {
+		diags.AddAttributeError(certificatePath, "[Validate Config] Invalid certificate", "No PEM encoded certificate is found.")
+	}
+	chain := parsePemCertificates(certificateChain, chainPath, &diags)
+	if len(chain) == 0 && strings.TrimSpace(certificateChain) != "" && !diags.HasError() {
+		diags.AddAttributeError(chainPath, "[Validate Config] Invalid certificate", "No PEM encoded certificate is found.")
+	}
+	certs = append(certs, chain...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	// The leaf certificate is the one matching the private key, fall back to
+	// the first certificate if the key is invalid.
+	leaf := 0
+	if strings.TrimSpace(sslKey) == "" {
+		leaf = findLeafCertificate(certs)
+	} else if key, err := parsePemPrivateKey(sslKey); err != nil {
+		diags.AddAttributeError(keyPath, "[Validate Config] Invalid private key", err.Error())
+	} else {
+		leaf = -1
+		for i, c := range certs {
+			if publicKeyEqual(c.cert.PublicKey, key.Public()) {
+				leaf = i
+				break
+			}
+		}
+		if leaf < 0 {
+			diags.AddAttributeError(keyPath, "[Validate Config] Private key mismatch",
+				fmt.Sprintf("The private key does not match the certificate %q.", certs[0].cert.Subject.String()))
+			leaf = 0
+		}
+	}
+
+	// Follow the issuers from the leaf certificate.
+	used := make([]bool, len(certs))
+	used[leaf] = true
+	ordered := []parsedCertificate{certs[leaf]}
+	for current := certs[leaf].cert; !bytes.Equal(current.RawIssuer, current.RawSubject); {
+		next := -1
+		for i, c := range certs {
+			if !used[i] && bytes.Equal(c.cert.RawSubject, current.RawIssuer) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			break
+		}
+		used[next] = true
+		ordered = append(ordered, certs[next])
+		current = certs[next].cert
+	}
+	for i, c := range certs {
+		if !used[i] {
+			diags.AddAttributeError(c.path, "[Validate Config] Invalid certificate chain",
+				fmt.Sprintf("The certificate %q is not an issuer in the chain of the certificate %q.", c.cert.Subject.String(), certs[leaf].cert.Subject.String()))
+		}
+	}
+
+	if diags.HasError() {
+		return nil, diags
+	}
+	return &certificateBundle{chain: ordered}, diags
+}
+
+// findLeafCertificate returns the index of the first certificate which is not
+// the issuer of any other certificate, or 0 if there is none.
+func findLeafCertificate(certs []parsedCertificate) int {
+	for i, c := range certs {
+		issuer := false
+		for j, other := range certs {
+			if i != j && bytes.Equal(c.cert.RawSubject, other.cert.RawIssuer) {
+				issuer = true
+				break
+			}
+		}
+		if !issuer {
+			return i
+		}
+	}
+	return 0
+}
+
+func parsePemCertificates(s string, p path.Path, diags *diag.Diagnostics) []parsedCertificate {
+	certs := make([]parsedCertificate, 0)
+	for i, block := range decodePemBlocks(s) {
+		if block.Type != "CERTIFICATE" {
+			diags.AddAttributeError(p, "[Validate Config] Invalid certificate",
+				fmt.Sprintf("PEM block #%d is %q, only CERTIFICATE is allowed.", i+1, block.Type))
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			diags.AddAttributeError(p, "[Validate Config] Invalid certificate",
+				fmt.Sprintf("PEM block #%d fails to be parsed: %v", i+1, err))
+			continue
+		}
+		certs = append(certs, parsedCertificate{cert: cert, path: p})
+	}
+	return certs
+}
+
+// parsePemPrivateKey parses the PKCS #1, PKCS #8 or SEC 1 private key.
+func parsePemPrivateKey(s string) (crypto.Signer, error) {
+	blocks := decodePemBlocks(s)
+	if len(blocks) == 0 {
+		return nil, errors.New("no PEM encoded private key is found")
+	}
+	block := blocks[0]
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q, must be RSA PRIVATE KEY, EC PRIVATE KEY or PRIVATE KEY", block.Type)
+	}
+}
+
+// parsePemCertificateRequest parses the PEM encoded CSR.
+func parsePemCertificateRequest(s string) (*x509.CertificateRequest, error) {
+	for _, block := range decodePemBlocks(s) {
+		if block.Type == "CERTIFICATE REQUEST" || block.Type == "NEW CERTIFICATE REQUEST" {
+			return x509.ParseCertificateRequest(block.Bytes)
+		}
+	}
+	return nil, errors.New("no PEM encoded certificate request is found")
+}
+
+// publicKeyEqual returns true if the public keys are the same.
+func publicKeyEqual(a, b crypto.PublicKey) bool {
+	publicKey, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && publicKey.Equal(b)
+}
+
+// queryCertificateDnsNames returns the common name and the subject alternative
+// names of the certificate at vendor, empty if vendor returns none of them.
+func queryCertificateDnsNames(client *cdnetworksapi.Client, certificateId string) ([]string, error) {
+	queryCertificateInfoResponse, err := client.QueryCertificateInfo(certificateId)
+	if err != nil {
+		return nil, err
+	}
+	if *queryCertificateInfoResponse.Code == certificateNotFoundCode {
+		return nil, fmt.Errorf("certificate %s is not found", certificateId)
+	} else if *queryCertificateInfoResponse.Code != 0 {
+		return nil, errors.New(*queryCertificateInfoResponse.Message)
+	}
+
+	dnsNames := make([]string, 0)
+	if info := queryCertificateInfoResponse.QueryCertificateInfoResponseData; info != nil {
+		if info.CommonName != nil && *info.CommonName != "" {
+			dnsNames = append(dnsNames, *info.CommonName)
+		}
+		dnsNames = append(dnsNames, info.SubjectAlternativeNames...)
+	}
+	if len(dnsNames) > 0 {
+		return dnsNames, nil
+	}
+
+	queryCertificateResponse, err := client.QueryCertificate(certificateId)
+	if err != nil {
+		return nil, err
+	}
+	for _, dnsName := range queryCertificateResponse.DnsNames {
+		if dnsName != nil && *dnsName != "" {
+			dnsNames = append(dnsNames, *dnsName)
+		}
+	}
+	return dnsNames, nil
+}
+
+// certificateCoversDnsName returns true if any of dnsNames of a certificate
+// covers name. A wildcard name covers exactly one label, e.g. *.example.com
+// covers www.example.com but not example.com or a.www.example.com.
+func certificateCoversDnsName(dnsNames []string, name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for _, dnsName := range dnsNames {
+		dnsName = strings.ToLower(strings.TrimSuffix(dnsName, "."))
+		if dnsName == name {
+			return true
+		}
+		if strings.HasPrefix(dnsName, "*.") {
+			label, rest, found := strings.Cut(name, ".")
+			if found && label != "" && label != "*" && rest == dnsName[len("*."):] {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package cdnetworks
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+var (
+	_ datasource.DataSource              = &purgeQuotaDataSource{}
+	_ datasource.DataSourceWithConfigure = &purgeQuotaDataSource{}
+)
+
+type purgeQuotaDataSourceModel struct {
+	UrlQuota          types.Int64 `tfsdk:"url_quota"`
+	UrlUsed           types.Int64 `tfsdk:"url_used"`
+	UrlRemaining      types.Int64 `tfsdk:"url_remaining"`
+	DirQuota          types.Int64 `tfsdk:"dir_quota"`
+	DirUsed           types.Int64 `tfsdk:"dir_used"`
+	DirRemaining      types.Int64 `tfsdk:"dir_remaining"`
+	PrefetchQuota     types.Int64 `tfsdk:"prefetch_quota"`
+	PrefetchUsed      types.Int64 `tfsdk:"prefetch_used"`
+	PrefetchRemaining types.Int64 `tfsdk:"prefetch_remaining"`
+}
+
+type purgeQuotaDataSource struct {
+	client *cdnetworksapi.Client
+}
+
+func NewPurgeQuotaDataSource() datasource.DataSource {
+	return &purgeQuotaDataSource{}
+}
+
+func (d *purgeQuotaDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_purge_quota"
+}
+
+func (d *purgeQuotaDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This data source provides the daily purge and prefetch quota of the account.",
+		Attributes: map[string]schema.Attribute{
+			"url_quota": schema.Int64Attribute{
+				Description: "Daily quota of url purge",
+				Computed:    true,
+			},
+			"url_used": schema.Int64Attribute{
+				Description: "Number of urls purged today",
+				Computed:    true,
+			},
+			"url_remaining": schema.Int64Attribute{
+				Description: "Remaining quota of url purge today",
+				Computed:    true,
+			},
+			"dir_quota": schema.Int64Attribute{
+				Description: "Daily quota of dir purge",
+				Computed:    true,
+			},
+			"dir_used": schema.Int64Attribute{
+				Description: "Number of dirs purged today",
+				Computed:    true,
+			},
+			"dir_remaining": schema.Int64Attribute{
+				Description: "Remaining quota of dir purge today",
+				Computed:    true,
+			},
+			"prefetch_quota": schema.Int64Attribute{
+				Description: "Daily quota of prefetch",
+				Computed:    true,
+			},
+			"prefetch_used": schema.Int64Attribute{
+				Description: "Number of urls prefetched today",
+				Computed:    true,
+			},
+			"prefetch_remaining": schema.Int64Attribute{
+				Description: "Remaining quota of prefetch today",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *purgeQuotaDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.client = req.ProviderData.(*providerData).client
+}
+
+func (d *purgeQuotaDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state purgeQuotaDataSourceModel
+
+	queryContentQuotaResponse, err := d.client.QueryContentQuota()
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Query Purge Quota", err.Error())
+		return
+	}
+
+	state.UrlQuota, state.UrlUsed, state.UrlRemaining = quotaUsage(queryContentQuotaResponse.UrlQuota, queryContentQuotaResponse.UrlRemain)
+	state.DirQuota, state.DirUsed, state.DirRemaining = quotaUsage(queryContentQuotaResponse.DirQuota, queryContentQuotaResponse.DirRemain)
+	state.PrefetchQuota, state.PrefetchUsed, state.PrefetchRemaining = quotaUsage(queryContentQuotaResponse.PrefetchQuota, queryContentQuotaResponse.PrefetchRemain)
+
+	resp.State.Set(ctx, &state)
+}
+
+// quotaUsage returns quota, used and remaining, used is null if either quota
+// or remaining is not returned.
+func quotaUsage(quota, remain *int64) (types.Int64, types.Int64, types.Int64) {
+	used := types.Int64Null()
+	if quota != nil && remain != nil {
+		used = types.Int64Value(*quota - *remain)
+	}
+	return types.Int64PointerValue(quota), used, types.Int64PointerValue(remain)
+}
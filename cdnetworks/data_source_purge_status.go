@@ -0,0 +1,132 @@
+package cdnetworks
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+var (
+	_ datasource.DataSource              = &purgeStatusDataSource{}
+	_ datasource.DataSourceWithConfigure = &purgeStatusDataSource{}
+)
+
+type purgeResult struct {
+	Url          types.String `tfsdk:"url"`
+	Type         types.String `tfsdk:"type"`
+	Status       types.String `tfsdk:"status"`
+	CreatedTime  types.String `tfsdk:"created_time"`
+	FinishedTime types.String `tfsdk:"finished_time"`
+}
+
+type purgeStatusDataSourceModel struct {
+	TaskId  types.String   `tfsdk:"task_id"`
+	Status  types.String   `tfsdk:"status"`
+	Results []*purgeResult `tfsdk:"results"`
+}
+
+type purgeStatusDataSource struct {
+	client *cdnetworksapi.Client
+}
+
+func NewPurgeStatusDataSource() datasource.DataSource {
+	return &purgeStatusDataSource{}
+}
+
+func (d *purgeStatusDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_purge_status"
+}
+
+func (d *purgeStatusDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This data source provides the status of a submitted purge task.",
+		Attributes: map[string]schema.Attribute{
+			"task_id": schema.StringAttribute{
+				Description: "The id of the purge task.",
+				Required:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Overall status of the purge task. SUCCESS: all urls/dirs are purged. FAIL: all urls/dirs are processed and at least one of them failed. WAIT: the task is in progress.",
+				Computed:    true,
+			},
+			"results": schema.ListNestedAttribute{
+				Description: "Purge result of each url/dir",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"url": schema.StringAttribute{
+							Description: "The purged url or dir",
+							Computed:    true,
+						},
+						"type": schema.StringAttribute{
+							Description: "url or dir",
+							Computed:    true,
+						},
+						"status": schema.StringAttribute{
+							Description: "SUCCESS, FAIL or WAIT",
+							Computed:    true,
+						},
+						"created_time": schema.StringAttribute{
+							Description: "The time the url/dir is submitted",
+							Computed:    true,
+						},
+						"finished_time": schema.StringAttribute{
+							Description: "The time the url/dir is processed",
+							Computed:    true,
+						},
+					},
+				},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *purgeStatusDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.client = req.ProviderData.(*providerData).client
+}
+
+func (d *purgeStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model purgeStatusDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	queryPurgeStatusResponse, err := d.client.QueryPurgeStatus(model.TaskId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Query Purge Status", err.Error())
+		return
+	}
+
+	status := cdnetworksapi.ContentTaskStatusSuccess
+	if len(queryPurgeStatusResponse.ResultDetail) == 0 {
+		status = cdnetworksapi.ContentTaskStatusInProgress
+	}
+	model.Results = make([]*purgeResult, 0)
+	for _, detail := range queryPurgeStatusResponse.ResultDetail {
+		model.Results = append(model.Results, &purgeResult{
+			Url:          types.StringPointerValue(detail.Url),
+			Type:         types.StringPointerValue(detail.Type),
+			Status:       types.StringPointerValue(detail.Status),
+			CreatedTime:  types.StringPointerValue(detail.CreatedTime),
+			FinishedTime: types.StringPointerValue(detail.FinishedTime),
+		})
+		switch {
+		case detail.Status == nil || (*detail.Status != cdnetworksapi.ContentTaskStatusSuccess && *detail.Status != cdnetworksapi.ContentTaskStatusFailed):
+			status = cdnetworksapi.ContentTaskStatusInProgress
+		case *detail.Status == cdnetworksapi.ContentTaskStatusFailed && status == cdnetworksapi.ContentTaskStatusSuccess:
+			status = cdnetworksapi.ContentTaskStatusFailed
+		}
+	}
+	model.Status = types.StringValue(status)
+
+	resp.State.Set(ctx, &model)
+}
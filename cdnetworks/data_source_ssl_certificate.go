@@ -2,9 +2,15 @@ package cdnetworks
 
 import (
 	"context"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
@@ -16,17 +22,34 @@ var (
 )
 
 type certificate struct {
-	Id   types.String `tfsdk:"certificate_id"`
-	Name types.String `tfsdk:"name"`
+	Id             types.String `tfsdk:"certificate_id"`
+	Name           types.String `tfsdk:"name"`
+	Comment        types.String `tfsdk:"comment"`
+	NotBefore      types.String `tfsdk:"not_before"`
+	NotAfter       types.String `tfsdk:"not_after"`
+	Issuer         types.String `tfsdk:"issuer"`
+	Serial         types.String `tfsdk:"serial"`
+	CrtMd5         types.String `tfsdk:"crt_md5"`
+	KeyMd5         types.String `tfsdk:"key_md5"`
+	CaMd5          types.String `tfsdk:"ca_md5"`
+	DnsNames       types.List   `tfsdk:"dns_names"`
+	RelatedDomains types.List   `tfsdk:"related_domains"`
 }
 
 type certDataSourceModel struct {
-	CertNameList types.List     `tfsdk:"cert_name_list"`
-	CertList     []*certificate `tfsdk:"cert_list"`
+	CertNameList      types.List     `tfsdk:"cert_name_list"`
+	CertificateId     types.String   `tfsdk:"certificate_id"`
+	DnsName           types.String   `tfsdk:"dns_name"`
+	BoundDomain       types.String   `tfsdk:"bound_domain"`
+	ExpiresWithinDays types.Int64    `tfsdk:"expires_within_days"`
+	ExpiryWarningDays types.Int64    `tfsdk:"expiry_warning_days"`
+	FailOnExpired     types.Bool     `tfsdk:"fail_on_expired"`
+	CertList          []*certificate `tfsdk:"cert_list"`
 }
 
 type certDataSource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	expiryWarningDays int64
 }
 
 func NewCertDataSource() datasource.DataSource {
@@ -39,13 +62,43 @@ func (d *certDataSource) Metadata(_ context.Context, req datasource.MetadataRequ
 
 func (d *certDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "This data source provides certificates configured in cdnetworks.",
+		Description: "This data source provides certificates configured in cdnetworks. The filters are combined, only the certificates matching all of them are retrieved.",
 		Attributes: map[string]schema.Attribute{
 			"cert_name_list": schema.ListAttribute{
 				Description: "List of certificate name.If cert_name_list is null,retrieve all certificates.If cert_name_list is not null (includes empty), retrive certificates with specific name.",
 				ElementType: types.StringType,
 				Optional:    true,
 			},
+			"certificate_id": schema.StringAttribute{
+				Description: "Retrieve the certificate with the ID.",
+				Optional:    true,
+			},
+			"dns_name": schema.StringAttribute{
+				Description: "Retrieve the certificates covering the DNS name, wildcard names of the certificates are matched. E.g: *.example.com covers www.example.com",
+				Optional:    true,
+			},
+			"bound_domain": schema.StringAttribute{
+				Description: "Retrieve the certificates used by the domain, either the domain name or the domain ID.",
+				Optional:    true,
+			},
+			"expires_within_days": schema.Int64Attribute{
+				Description: "Retrieve the certificates expiring within the number of days, including the expired ones.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"expiry_warning_days": schema.Int64Attribute{
+				Description: "Warn if any retrieved certificate expires within the number of days. Defaults to expiry_warning_days of the provider.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"fail_on_expired": schema.BoolAttribute{
+				Description: "Fail if any retrieved certificate is expired, otherwise only warn. Default: false",
+				Optional:    true,
+			},
 			"cert_list": schema.ListNestedAttribute{
 				Description: "List of certificate",
 				NestedObject: schema.NestedAttributeObject{
@@ -58,6 +111,59 @@ func (d *certDataSource) Schema(_ context.Context, req datasource.SchemaRequest,
 							Description: "Certificate ID",
 							Computed:    true,
 						},
+						"comment": schema.StringAttribute{
+							Description: "Comment of the certificate",
+							Computed:    true,
+						},
+						"not_before": schema.StringAttribute{
+							Description: "The time from which the certificate is valid, in RFC3339 format.",
+							Computed:    true,
+						},
+						"not_after": schema.StringAttribute{
+							Description: "The time after which the certificate is expired, in RFC3339 format.",
+							Computed:    true,
+						},
+						"issuer": schema.StringAttribute{
+							Description: "Issuer of the certificate.",
+							Computed:    true,
+						},
+						"serial": schema.StringAttribute{
+							Description: "Serial number of the certificate.",
+							Computed:    true,
+						},
+						"crt_md5": schema.StringAttribute{
+							Description: "MD5 fingerprint of the certificate.",
+							Computed:    true,
+						},
+						"key_md5": schema.StringAttribute{
+							Description: "MD5 fingerprint of the private key.",
+							Computed:    true,
+						},
+						"ca_md5": schema.StringAttribute{
+							Description: "MD5 fingerprint of the CA certificates.",
+							Computed:    true,
+						},
+						"dns_names": schema.ListAttribute{
+							Description: "DNS names covered by the certificate.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"related_domains": schema.ListNestedAttribute{
+							Description: "Domains using the certificate.",
+							Computed:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"domain_id": schema.StringAttribute{
+										Description: "Domain ID",
+										Computed:    true,
+									},
+									"domain_name": schema.StringAttribute{
+										Description: "Domain name",
+										Computed:    true,
+									},
+								},
+							},
+						},
 					},
 				},
 				Computed: true,
@@ -70,7 +176,9 @@ func (d *certDataSource) Configure(_ context.Context, req datasource.ConfigureRe
 	if req.ProviderData == nil {
 		return
 	}
-	d.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	d.client = data.client
+	d.expiryWarningDays = data.expiryWarningDays
 }
 
 func (d *certDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -81,7 +189,7 @@ func (d *certDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	state.CertNameList = model.CertNameList
+	state = model
 
 	queryCertificateListResponse, err := d.client.QueryCertificateList()
 	if err != nil {
@@ -89,31 +197,135 @@ func (d *certDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	state.CertList = make([]*certificate, 0)
+	// Certificates are retrieved in the order of cert_name_list if it is
+	// specified, null for the name not found.
+	ssls := make([]*cdnetworksapi.SslCertificate, 0)
 	if state.CertNameList.IsNull() {
-		for _, ssl := range queryCertificateListResponse.SslCertificates {
-			state.CertList = append(state.CertList,
-				&certificate{
-					Id:   types.StringPointerValue(ssl.CertificateId),
-					Name: types.StringPointerValue(ssl.Name),
-				},
-			)
-		}
+		ssls = queryCertificateListResponse.SslCertificates
 	} else {
 		for _, name := range state.CertNameList.Elements() {
-			var c *certificate
+			var found *cdnetworksapi.SslCertificate
 			for _, ssl := range queryCertificateListResponse.SslCertificates {
 				if name.(types.String).ValueString() == *ssl.Name {
-					c = &certificate{
-						Id:   types.StringPointerValue(ssl.CertificateId),
-						Name: types.StringPointerValue(ssl.Name),
-					}
+					found = ssl
 					break
 				}
 			}
-			state.CertList = append(state.CertList, c)
+			ssls = append(ssls, found)
 		}
 	}
 
+	now := time.Now()
+	state.CertList = make([]*certificate, 0)
+	for _, ssl := range ssls {
+		if ssl == nil {
+			state.CertList = append(state.CertList, nil)
+			continue
+		}
+		if !model.matches(ssl, now) {
+			continue
+		}
+		dnsNames, err := d.certificateDnsNames(ssl)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Query Certificate", err.Error())
+			return
+		}
+		if !model.DnsName.IsNull() && !certificateCoversDnsName(dnsNames, model.DnsName.ValueString()) {
+			continue
+		}
+		c, diags := newCertificate(ctx, ssl, dnsNames)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.CertList = append(state.CertList, c)
+	}
+
+	warningDays := d.expiryWarningDays
+	if !model.ExpiryWarningDays.IsNull() {
+		warningDays = model.ExpiryWarningDays.ValueInt64()
+	}
+	for i, c := range state.CertList {
+		if c == nil {
+			continue
+		}
+		if notAfter, ok := parseCertificateTime(c.NotAfter.ValueString()); ok {
+			checkCertificateExpiry(path.Root("cert_list").AtListIndex(i).AtName("not_after"), c.Name.ValueString(), notAfter, warningDays, model.FailOnExpired.ValueBool(), now, &resp.Diagnostics)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	resp.State.Set(ctx, &state)
 }
+
+// matches returns true if ssl matches all the filters of the model except
+// dns_name, which is matched against certificateDnsNames.
+func (m *certDataSourceModel) matches(ssl *cdnetworksapi.SslCertificate, now time.Time) bool {
+	if !m.CertificateId.IsNull() && (ssl.CertificateId == nil || *ssl.CertificateId != m.CertificateId.ValueString()) {
+		return false
+	}
+
+	if !m.BoundDomain.IsNull() {
+		bound := false
+		for _, domain := range ssl.RelatedDomains {
+			if (domain.DomainId != nil && *domain.DomainId == m.BoundDomain.ValueString()) ||
+				(domain.DomainName != nil && strings.EqualFold(*domain.DomainName, m.BoundDomain.ValueString())) {
+				bound = true
+				break
+			}
+		}
+		if !bound {
+			return false
+		}
+	}
+
+	if !m.ExpiresWithinDays.IsNull() {
+		if ssl.CertificateValidityTo == nil {
+			return false
+		}
+		notAfter, ok := parseCertificateTime(*ssl.CertificateValidityTo)
+		if !ok || notAfter.After(now.AddDate(0, 0, int(m.ExpiresWithinDays.ValueInt64()))) {
+			return false
+		}
+	}
+	return true
+}
+
+// certificateDnsNames returns the DNS names of ssl in the list, or queries them
+// as queryCertificateDnsNames if the list has none.
+func (d *certDataSource) certificateDnsNames(ssl *cdnetworksapi.SslCertificate) ([]string, error) {
+	dnsNames := make([]string, 0, len(ssl.DnsNames))
+	for _, dnsName := range ssl.DnsNames {
+		if dnsName != nil && *dnsName != "" {
+			dnsNames = append(dnsNames, *dnsName)
+		}
+	}
+	if len(dnsNames) > 0 || ssl.CertificateId == nil {
+		return dnsNames, nil
+	}
+	return queryCertificateDnsNames(d.client, *ssl.CertificateId)
+}
+
+func newCertificate(ctx context.Context, ssl *cdnetworksapi.SslCertificate, dnsNames []string) (*certificate, diag.Diagnostics) {
+	var diags, d diag.Diagnostics
+	c := &certificate{
+		Id:        types.StringPointerValue(ssl.CertificateId),
+		Name:      types.StringPointerValue(ssl.Name),
+		Comment:   types.StringPointerValue(ssl.Comment),
+		NotBefore: certificateTimeValue(ssl.CertificateValidityFrom),
+		NotAfter:  certificateTimeValue(ssl.CertificateValidityTo),
+		Issuer:    types.StringPointerValue(ssl.CertificateIssuer),
+		Serial:    types.StringPointerValue(ssl.CertificateSerial),
+		CrtMd5:    md5Value(ssl.CrtMd5),
+		KeyMd5:    md5Value(ssl.KeyMd5),
+		CaMd5:     md5Value(ssl.CaMd5),
+	}
+
+	c.DnsNames, d = stringsValue(ctx, dnsNames)
+	diags.Append(d...)
+	c.RelatedDomains, d = certificateDomainsValue(ssl.RelatedDomains)
+	diags.Append(d...)
+	return c, diags
+}
@@ -0,0 +1,69 @@
+package cdnetworks
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+const (
+	deploymentStatusDeployed   = "Deployed"
+	deploymentStatusInProgress = "InProgress"
+)
+
+// waitForDeploymentAttribute and deploymentStatusAttribute are shared by the
+// resources which wait for the deployment of the domain after changes.
+func waitForDeploymentAttribute() schema.Attribute {
+	return &schema.BoolAttribute{
+		Description: "Wait until the changes are deployed. Defaults to wait_for_deployment of the provider. " +
+			"Set to false and use st-cdnetworks_deployment_wait to wait once for several changes of the same domain.",
+		Optional: true,
+	}
+}
+
+func deploymentStatusAttribute() schema.Attribute {
+	return &schema.StringAttribute{
+		Description: "The deployment status of the domain after the last change, InProgress if the deployment is not waited. It is refreshed until Deployed.",
+		Computed:    true,
+	}
+}
+
+// shouldWaitForDeployment returns wait_for_deployment of the resource if set,
+// otherwise the provider default.
+func shouldWaitForDeployment(waitForDeployment types.Bool, providerDefault bool) bool {
+	if waitForDeployment.IsNull() || waitForDeployment.IsUnknown() {
+		return providerDefault
+	}
+	return waitForDeployment.ValueBool()
+}
+
+// waitForDeployment waits until the domain is deployed if wait is true,
+// returns the deployment status to be saved.
+func waitForDeployment(ctx context.Context, client *cdnetworksapi.Client, domainId string, wait bool) (types.String, error) {
+	if !wait {
+		return types.StringValue(deploymentStatusInProgress), nil
+	}
+	err := utils.WaitForDomainDeployed(ctx, client, domainId)
+	if err != nil {
+		return types.StringValue(deploymentStatusInProgress), err
+	}
+	return types.StringValue(deploymentStatusDeployed), nil
+}
+
+// refreshDeploymentStatus queries the domain status if the deployment is not
+// known to be completed.
+func refreshDeploymentStatus(client *cdnetworksapi.Client, domainId string, status types.String, diags *diag.Diagnostics) types.String {
+	if status.ValueString() == deploymentStatusDeployed {
+		return status
+	}
+	queryCdnDomainResponse, err := client.QueryCdnDomain(domainId)
+	if err != nil {
+		diags.AddWarning("[API ERROR] Fail to refresh deployment status", err.Error())
+		return status
+	}
+	return types.StringPointerValue(queryCdnDomainResponse.Status)
+}
@@ -3,14 +3,20 @@ package model
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
@@ -119,6 +125,22 @@ var DomainSchema = schema.Schema{
 					Optional: true,
 					Computed: true,
 				},
+				"origin_port": schema.Int64Attribute{
+					Description: `Back-to-origin port. The value range is 1-65535. If it is empty, the port follows the protocol of the back-to-origin request.`,
+					Optional:    true,
+					Validators: []validator.Int64{
+						int64validator.Between(1, 65535),
+					},
+				},
+				"origin_host": schema.StringAttribute{
+					Description: `The origin domain name used in the back-to-origin request. It should be domain format and does not exceed 128 characters.`,
+					Optional:    true,
+					Validators: []validator.String{
+						stringvalidator.LengthAtMost(128),
+						stringvalidator.RegexMatches(regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)*[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`),
+							"Must be a domain name, e.g. origin.example.com"),
+					},
+				},
 			},
 		},
 		"control_group": &schema.SingleNestedAttribute{
@@ -164,6 +186,8 @@ var DomainSchema = schema.Schema{
 var originConfigModelAttributeTypes = map[string]attr.Type{
 	"origin_ips":                 types.ListType{}.WithElementType(types.StringType),
 	"default_origin_host_header": types.StringType,
+	"origin_port":                types.Int64Type,
+	"origin_host":                types.StringType,
 }
 
 type DomainResourceModel struct {
@@ -183,6 +207,7 @@ type DomainResourceModel struct {
 	OriginConfig      types.Object       `tfsdk:"origin_config"`
 	ControlGroup      *ControlGroupModel `tfsdk:"control_group"`
 	CacheHost         types.String       `tfsdk:"cache_host"`
+	Timeouts          timeouts.Value     `tfsdk:"timeouts"`
 }
 
 type ControlGroupModel struct {
@@ -196,7 +221,10 @@ type accountListModel struct {
 	LoginName types.String `tfsdk:"login_name"`
 }
 
-func (model *DomainResourceModel) BuildApiOriginConfig() *cdnetworksapi.OriginConfig {
+// BuildApiOriginConfig builds the origin config of the request. prior is the
+// state before the update, nil on creation. Omitted fields are kept by vendor,
+// so origin_port and origin_host removed from the config are cleared explicitly.
+func (model *DomainResourceModel) BuildApiOriginConfig(prior *DomainResourceModel) *cdnetworksapi.OriginConfig {
 	config := &cdnetworksapi.OriginConfig{}
 	for k, v := range model.OriginConfig.Attributes() {
 		if k == "origin_ips" {
@@ -206,6 +234,22 @@ func (model *DomainResourceModel) BuildApiOriginConfig() *cdnetworksapi.OriginCo
 			config.OriginIps = &s
 		} else if k == "default_origin_host_header" {
 			config.DefaultOriginHostHeader = v.(types.String).ValueStringPointer()
+		} else if k == "origin_port" && !v.IsNull() && !v.IsUnknown() {
+			port := strconv.FormatInt(v.(types.Int64).ValueInt64(), 10)
+			config.OriginPort = &port
+		} else if k == "origin_host" {
+			config.OriginHost = v.(types.String).ValueStringPointer()
+		}
+	}
+
+	if prior != nil && !prior.OriginConfig.IsNull() && !prior.OriginConfig.IsUnknown() {
+		priorAttributes := prior.OriginConfig.Attributes()
+		empty := ""
+		if config.OriginPort == nil && !priorAttributes["origin_port"].IsNull() {
+			config.OriginPort = &empty
+		}
+		if config.OriginHost == nil && !priorAttributes["origin_host"].IsNull() {
+			config.OriginHost = &empty
 		}
 	}
 
@@ -230,10 +274,22 @@ func (model *DomainResourceModel) UpdateDomainFromApiConfig(ctx context.Context,
 		if config.OriginConfig.DefaultOriginHostHeader != nil {
 			defaultOriginHeader = types.StringPointerValue(config.OriginConfig.DefaultOriginHostHeader)
 		}
+		originPort := types.Int64Null()
+		if config.OriginConfig.OriginPort != nil && *config.OriginConfig.OriginPort != "" {
+			if port, err := strconv.ParseInt(*config.OriginConfig.OriginPort, 10, 64); err == nil {
+				originPort = types.Int64Value(port)
+			}
+		}
+		originHost := types.StringNull()
+		if config.OriginConfig.OriginHost != nil && *config.OriginConfig.OriginHost != "" {
+			originHost = types.StringPointerValue(config.OriginConfig.OriginHost)
+		}
 		iplistModel, _ := types.ListValueFrom(ctx, types.StringType, strings.Split(*config.OriginConfig.OriginIps, utils.Separator))
 		model.OriginConfig = types.ObjectValueMust(originConfigModelAttributeTypes, map[string]attr.Value{
 			"origin_ips":                 iplistModel,
 			"default_origin_host_header": defaultOriginHeader,
+			"origin_port":                originPort,
+			"origin_host":                originHost,
 		})
 	}
 }
@@ -259,12 +315,13 @@ func (model *DomainResourceModel) Check() error {
 }
 
 func (model *DomainResourceModel) Fill() {
-	defaulOriginHostHeader := model.OriginConfig.Attributes()["default_origin_host_header"]
-	if defaulOriginHostHeader.IsUnknown() {
-		ips := model.OriginConfig.Attributes()["origin_ips"]
+	attributes := model.OriginConfig.Attributes()
+	if attributes["default_origin_host_header"].IsUnknown() {
 		model.OriginConfig = types.ObjectValueMust(originConfigModelAttributeTypes, map[string]attr.Value{
-			"origin_ips":                 ips,
+			"origin_ips":                 attributes["origin_ips"],
 			"default_origin_host_header": model.Domain,
+			"origin_port":                attributes["origin_port"],
+			"origin_host":                attributes["origin_host"],
 		})
 	}
 }
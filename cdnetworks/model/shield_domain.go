@@ -0,0 +1,94 @@
+package model
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ShieldDomainSchema is DomainSchema with the origin shield attributes.
+var ShieldDomainSchema = func() schema.Schema {
+	attributes := make(map[string]schema.Attribute)
+	for k, v := range DomainSchema.Attributes {
+		attributes[k] = v
+	}
+	attributes["shield_region"] = &schema.StringAttribute{
+		Description: "The region of the shield nodes, which fetch contents from origin on behalf of the edge nodes. E.g: mainland_china, overseas",
+		Required:    true,
+	}
+	attributes["shield_nodes"] = &schema.ListAttribute{
+		Description: "The shield nodes selected in shield_region. If it is empty, the shield nodes are selected by vendor.",
+		ElementType: types.StringType,
+		Optional:    true,
+		Computed:    true,
+		Validators: []validator.List{
+			listvalidator.SizeAtLeast(1),
+		},
+		PlanModifiers: []planmodifier.List{
+			listplanmodifier.UseStateForUnknown(),
+		},
+	}
+
+	return schema.Schema{
+		Description: "This resource provides the configuration of origin shield domain",
+		Attributes:  attributes,
+	}
+}()
+
+// ShieldDomainResourceModel is DomainResourceModel with the origin shield
+// fields. The framework does not support embedded struct, thus it is read and
+// written attribute by attribute with GetFrom and SetTo instead of Get and Set.
+type ShieldDomainResourceModel struct {
+	DomainResourceModel `tfsdk:"-"`
+	ShieldRegion        types.String `tfsdk:"shield_region"`
+	ShieldNodes         types.List   `tfsdk:"shield_nodes"`
+}
+
+// AttributeGetter is implemented by tfsdk.Config, tfsdk.Plan and tfsdk.State.
+type AttributeGetter interface {
+	GetAttribute(ctx context.Context, p path.Path, target interface{}) diag.Diagnostics
+}
+
+// AttributeSetter is implemented by *tfsdk.Plan and *tfsdk.State.
+type AttributeSetter interface {
+	SetAttribute(ctx context.Context, p path.Path, val interface{}) diag.Diagnostics
+}
+
+func (model *ShieldDomainResourceModel) GetFrom(ctx context.Context, data AttributeGetter) diag.Diagnostics {
+	var diags diag.Diagnostics
+	forEachAttribute(reflect.ValueOf(model).Elem(), func(name string, field reflect.Value) {
+		diags.Append(data.GetAttribute(ctx, path.Root(name), field.Addr().Interface())...)
+	})
+	return diags
+}
+
+func (model *ShieldDomainResourceModel) SetTo(ctx context.Context, data AttributeSetter) diag.Diagnostics {
+	var diags diag.Diagnostics
+	forEachAttribute(reflect.ValueOf(model).Elem(), func(name string, field reflect.Value) {
+		diags.Append(data.SetAttribute(ctx, path.Root(name), field.Interface())...)
+	})
+	return diags
+}
+
+// forEachAttribute calls fn with the fields of the struct v tagged by tfsdk,
+// including the fields of the embedded structs.
+func forEachAttribute(v reflect.Value, fn func(name string, field reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			forEachAttribute(v.Field(i), fn)
+			continue
+		}
+		if name := field.Tag.Get("tfsdk"); name != "" && name != "-" {
+			fn(name, v.Field(i))
+		}
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"os"
 	"sync"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
 )
@@ -28,8 +30,18 @@ func New() provider.Provider {
 type cdnetworksProvider struct{}
 
 type cdnetworksProviderModel struct {
-	Username types.String `tfsdk:"username"`
-	ApiKey   types.String `tfsdk:"api_key"`
+	Username          types.String `tfsdk:"username"`
+	ApiKey            types.String `tfsdk:"api_key"`
+	WaitForDeployment types.Bool   `tfsdk:"wait_for_deployment"`
+	ExpiryWarningDays types.Int64  `tfsdk:"expiry_warning_days"`
+}
+
+// providerData is passed to the resources and data sources, holding the
+// client and the provider level defaults of the resources.
+type providerData struct {
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
+	expiryWarningDays int64
 }
 
 // Metadata returns the provider type name.
@@ -52,6 +64,18 @@ func (p *cdnetworksProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"wait_for_deployment": schema.BoolAttribute{
+				Description: "Default of wait_for_deployment of the resources, whether to wait until the changes are deployed. Default: true",
+				Optional:    true,
+			},
+			"expiry_warning_days": schema.Int64Attribute{
+				Description: "Default of expiry_warning_days of the certificate resources and data sources, " +
+					"the number of days before the certificate expires to warn at plan time. Default: 30",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
 		},
 	}
 }
@@ -148,33 +172,56 @@ func (p *cdnetworksProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	data := &providerData{
+		client:            client,
+		waitForDeployment: config.WaitForDeployment.IsNull() || config.WaitForDeployment.ValueBool(),
+		expiryWarningDays: defaultExpiryWarningDays,
+	}
+	if !config.ExpiryWarningDays.IsNull() {
+		data.expiryWarningDays = config.ExpiryWarningDays.ValueInt64()
+	}
+	resp.DataSourceData = data
+	resp.ResourceData = data
 }
 
 func (p *cdnetworksProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewDomainDataSource,
 		NewCertDataSource,
+		NewPurgeStatusDataSource,
+		NewPurgeQuotaDataSource,
 	}
 }
 
 func (p *cdnetworksProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewSslCertificateResource,
+		NewCertificateSigningRequestResource,
+		NewAcmeCertificateResource,
 		NewContentAccelerationDomainResource,
 		NewFloodShieldDomainResource,
+		NewDynamicWebAccelerationDomainResource,
+		NewDownloadAccelerationDomainResource,
+		NewMediaLiveDomainResource,
+		NewShieldDomainResource,
 		NewDomainSslAssociationResource,
 		NewHttpHeaderConfigResource,
 		NewHttp2SettingsConfigResource,
 		NewAntiHotlinkingConfigResource,
 		NewBackToOriginProtocolRewriteConfigResource,
 		NewCacheTimeResource,
+		NewCacheKeyConfigResource,
 		NewQueryStringUrlConfigResource,
 		NewHttpCodeCacheConfigResource,
 		NewIgnoreProtocolResource,
 		NewIpv6Resource,
 		NewOriginRulesRewriteConfigResource,
+		NewOriginFailoverConfigResource,
+		NewRangeAndFollowConfigResource,
 		NewUrlSignResource,
+		NewVideoDragConfigResource,
+		NewPurgeResource,
+		NewPrefetchResource,
+		NewDeploymentWaitResource,
 	}
 }
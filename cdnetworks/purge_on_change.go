@@ -0,0 +1,107 @@
+package cdnetworks
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+type purgeOnChangeModel struct {
+	Paths       types.List `tfsdk:"paths"`
+	WholeDomain types.Bool `tfsdk:"whole_domain"`
+}
+
+// purgeOnChangeBlock is shared by the resources affecting the cached contents,
+// which purges the cached contents after the configuration is deployed.
+func purgeOnChangeBlock() schema.Block {
+	return &schema.SingleNestedBlock{
+		Description: `Submit a directory purge for the domain after the configuration is deployed, so that the changes take effect immediately instead of after the cached contents expire. The deployment is always waited before purging, regardless of wait_for_deployment.`,
+		Attributes: map[string]schema.Attribute{
+			"paths": &schema.ListAttribute{
+				Description: `The directories to be purged, must start and end with "/". E.g: /images/`,
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(regexp.MustCompile(`^/.*/$`), `Must start and end with "/"`),
+					),
+				},
+			},
+			"whole_domain": &schema.BoolAttribute{
+				Description: "Purge all the cached contents of the domain.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// check validates the block if it is configured, exactly one of paths and
+// whole_domain = true is required.
+func (model *purgeOnChangeModel) check() error {
+	if model == nil || model.Paths.IsUnknown() || model.WholeDomain.IsUnknown() {
+		return nil
+	}
+	hasPaths := len(model.Paths.Elements()) > 0
+	if hasPaths && model.WholeDomain.ValueBool() {
+		return errors.New("paths and whole_domain of purge_on_change cannot be set at the same time")
+	}
+	if !hasPaths && !model.WholeDomain.ValueBool() {
+		return errors.New("purge_on_change requires either paths or whole_domain = true")
+	}
+	return nil
+}
+
+// waitAndPurge waits for the deployment of the domain as waitForDeployment,
+// then purges as purge_on_change. The deployment is always waited if
+// purge_on_change is configured, purging before the changes are deployed
+// caches the stale contents again.
+func waitAndPurge(ctx context.Context, client *cdnetworksapi.Client, domainId string, wait bool, model *purgeOnChangeModel) (types.String, error) {
+	deploymentStatus, err := waitForDeployment(ctx, client, domainId, wait || model != nil)
+	if err != nil {
+		return deploymentStatus, err
+	}
+	return deploymentStatus, purgeOnChange(client, domainId, model)
+}
+
+// purgeOnChange submits a directory purge for both http and https of the
+// domain, does nothing if purge_on_change is not configured.
+func purgeOnChange(client *cdnetworksapi.Client, domainId string, model *purgeOnChangeModel) error {
+	if model == nil {
+		return nil
+	}
+
+	paths := make([]string, 0)
+	if model.WholeDomain.ValueBool() {
+		paths = append(paths, "/")
+	} else if !model.Paths.IsNull() && !model.Paths.IsUnknown() {
+		for _, p := range model.Paths.Elements() {
+			paths = append(paths, p.(types.String).ValueString())
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	queryCdnDomainResponse, err := client.QueryCdnDomain(domainId)
+	if err != nil {
+		return err
+	}
+	dirs := make([]*string, 0)
+	for _, scheme := range []string{"http://", "https://"} {
+		for _, p := range paths {
+			dir := scheme + *queryCdnDomainResponse.DomainName + p
+			dirs = append(dirs, &dir)
+		}
+	}
+	_, err = client.SubmitPurge(cdnetworksapi.SubmitPurgeRequest{
+		Dirs: dirs,
+	})
+	return err
+}
@@ -0,0 +1,631 @@
+package cdnetworks
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+const (
+	defaultAcmeRenewBeforeDays = 30
+
+	privateStateKeyIssued = "issued"
+)
+
+type acmeChallengeModel struct {
+	Type                   types.String `tfsdk:"type"`
+	PresentCommand         types.List   `tfsdk:"present_command"`
+	CleanUpCommand         types.List   `tfsdk:"cleanup_command"`
+	PropagationWaitSeconds types.Int64  `tfsdk:"propagation_wait_seconds"`
+}
+
+// hook returns the hook running the commands of the challenge.
+func (m *acmeChallengeModel) hook(ctx context.Context) (acmeChallengeHook, error) {
+	hook := &commandChallengeHook{
+		propagationWait: time.Duration(m.PropagationWaitSeconds.ValueInt64()) * time.Second,
+	}
+	if diags := m.PresentCommand.ElementsAs(ctx, &hook.presentCommand, false); diags.HasError() {
+		return nil, errors.New("fail to convert present_command")
+	}
+	if !m.CleanUpCommand.IsNull() {
+		if diags := m.CleanUpCommand.ElementsAs(ctx, &hook.cleanUpCommand, false); diags.HasError() {
+			return nil, errors.New("fail to convert cleanup_command")
+		}
+	}
+	return hook, nil
+}
+
+type acmeCertificateModel struct {
+	Id                      types.String        `tfsdk:"certificate_id"`
+	Name                    types.String        `tfsdk:"name"`
+	Comment                 types.String        `tfsdk:"comment"`
+	DirectoryUrl            types.String        `tfsdk:"directory_url"`
+	DirectoryCaCertificate  types.String        `tfsdk:"directory_ca_certificate"`
+	Email                   types.String        `tfsdk:"email"`
+	AccountKeyPem           types.String        `tfsdk:"account_key_pem"`
+	CommonName              types.String        `tfsdk:"common_name"`
+	SubjectAlternativeNames types.List          `tfsdk:"subject_alternative_names"`
+	KeyType                 types.String        `tfsdk:"key_type"`
+	RenewBeforeDays         types.Int64         `tfsdk:"renew_before_days"`
+	Challenge               *acmeChallengeModel `tfsdk:"challenge"`
+	CertificatePem          types.String        `tfsdk:"certificate_pem"`
+	IssuerPem               types.String        `tfsdk:"issuer_pem"`
+	PrivateKeyPem           types.String        `tfsdk:"private_key_pem"`
+	NotBefore               types.String        `tfsdk:"not_before"`
+	NotAfter                types.String        `tfsdk:"not_after"`
+	Timeouts                timeouts.Value      `tfsdk:"timeouts"`
+}
+
+// domains returns common_name followed by the other subject alternative
+// names, the duplicates are removed.
+func (m *acmeCertificateModel) domains(ctx context.Context) ([]string, error) {
+	var sans []string
+	if !m.SubjectAlternativeNames.IsNull() {
+		if diags := m.SubjectAlternativeNames.ElementsAs(ctx, &sans, false); diags.HasError() {
+			return nil, errors.New("fail to convert subject_alternative_names")
+		}
+	}
+	domains := []string{m.CommonName.ValueString()}
+	for _, san := range sans {
+		duplicated := false
+		for _, domain := range domains {
+			if strings.EqualFold(domain, san) {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			domains = append(domains, san)
+		}
+	}
+	return domains, nil
+}
+
+// setCertificateUnknown marks the issued certificate to be known after apply,
+// so that it is issued again.
+func (m *acmeCertificateModel) setCertificateUnknown() {
+	m.CertificatePem = types.StringUnknown()
+	m.IssuerPem = types.StringUnknown()
+	m.PrivateKeyPem = types.StringUnknown()
+	m.NotBefore = types.StringUnknown()
+	m.NotAfter = types.StringUnknown()
+}
+
+// copyCertificate copies the issued certificate of state.
+func (m *acmeCertificateModel) copyCertificate(state *acmeCertificateModel) {
+	m.CertificatePem = state.CertificatePem
+	m.IssuerPem = state.IssuerPem
+	m.PrivateKeyPem = state.PrivateKeyPem
+	m.NotBefore = state.NotBefore
+	m.NotAfter = state.NotAfter
+}
+
+// issuedCertificate is a certificate renewed but failed to be uploaded. It is
+// kept in the private state, so that the next apply uploads it instead of
+// issuing it again against the rate limits of the CA.
+type issuedCertificate struct {
+	DirectoryUrl   string   `json:"directory_url"`
+	Domains        []string `json:"domains"`
+	KeyType        string   `json:"key_type"`
+	CertificatePem string   `json:"certificate_pem"`
+	IssuerPem      string   `json:"issuer_pem"`
+	PrivateKeyPem  string   `json:"private_key_pem"`
+	NotBefore      string   `json:"not_before"`
+	NotAfter       string   `json:"not_after"`
+}
+
+// recordIssued records the certificate issued of m into private.
+func (m *acmeCertificateModel) recordIssued(ctx context.Context, private privateState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	domains, err := m.domains(ctx)
+	if err != nil {
+		diags.AddError("Fail to Record Issued Certificate", err.Error())
+		return diags
+	}
+	value, err := json.Marshal(issuedCertificate{
+		DirectoryUrl:   m.DirectoryUrl.ValueString(),
+		Domains:        domains,
+		KeyType:        m.KeyType.ValueString(),
+		CertificatePem: m.CertificatePem.ValueString(),
+		IssuerPem:      m.IssuerPem.ValueString(),
+		PrivateKeyPem:  m.PrivateKeyPem.ValueString(),
+		NotBefore:      m.NotBefore.ValueString(),
+		NotAfter:       m.NotAfter.ValueString(),
+	})
+	if err != nil {
+		diags.AddError("Fail to Record Issued Certificate", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, privateStateKeyIssued, value)
+}
+
+// reuseIssued sets the certificate of m to the one recorded in private, if it
+// is issued by the same directory for the same domains and key type, and it
+// does not expire within renew_before_days. It returns true if it is reused.
+func (m *acmeCertificateModel) reuseIssued(ctx context.Context, private privateState) (bool, diag.Diagnostics) {
+	if m.DirectoryUrl.IsUnknown() || m.CommonName.IsUnknown() || m.SubjectAlternativeNames.IsUnknown() ||
+		m.KeyType.IsUnknown() || m.RenewBeforeDays.IsUnknown() {
+		return false, nil
+	}
+	value, diags := private.GetKey(ctx, privateStateKeyIssued)
+	if diags.HasError() || len(value) == 0 {
+		return false, diags
+	}
+	var issued issuedCertificate
+	if err := json.Unmarshal(value, &issued); err != nil {
+		diags.AddWarning("Fail to Parse Issued Certificate", err.Error())
+		return false, diags
+	}
+	if issued.CertificatePem == "" {
+		return false, diags
+	}
+
+	domains, err := m.domains(ctx)
+	if err != nil || issued.DirectoryUrl != m.DirectoryUrl.ValueString() || issued.KeyType != m.KeyType.ValueString() ||
+		strings.Join(issued.Domains, ",") != strings.Join(domains, ",") {
+		return false, diags
+	}
+	notAfter, ok := parseCertificateTime(issued.NotAfter)
+	if !ok || time.Now().AddDate(0, 0, int(m.RenewBeforeDays.ValueInt64())).After(notAfter) {
+		return false, diags
+	}
+
+	m.CertificatePem = types.StringValue(issued.CertificatePem)
+	m.IssuerPem = types.StringValue(issued.IssuerPem)
+	m.PrivateKeyPem = types.StringValue(issued.PrivateKeyPem)
+	m.NotBefore = types.StringValue(issued.NotBefore)
+	m.NotAfter = types.StringValue(issued.NotAfter)
+	return true, diags
+}
+
+type acmeCertificateResource struct {
+	client *cdnetworksapi.Client
+}
+
+var (
+	_ resource.Resource               = &acmeCertificateResource{}
+	_ resource.ResourceWithConfigure  = &acmeCertificateResource{}
+	_ resource.ResourceWithModifyPlan = &acmeCertificateResource{}
+)
+
+func NewAcmeCertificateResource() resource.Resource {
+	return &acmeCertificateResource{}
+}
+
+func (r *acmeCertificateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_acme_certificate"
+}
+
+func (r *acmeCertificateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Obtain a certificate from an ACME directory, e.g. Let's Encrypt, and upload it as a SSL certificate. " +
+			"The certificate is renewed in place when it expires within renew_before_days at plan time, or the domains or key_type change.",
+		Attributes: map[string]schema.Attribute{
+			"certificate_id": &schema.StringAttribute{
+				Description: "Certificate ID",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": &schema.StringAttribute{
+				Description: "Certificate name",
+				Required:    true,
+			},
+			"comment": &schema.StringAttribute{
+				Description: "comment",
+				Optional:    true,
+			},
+			"directory_url": &schema.StringAttribute{
+				Description: "URL of the ACME directory. E.g: https://acme-v02.api.letsencrypt.org/directory",
+				Required:    true,
+			},
+			"directory_ca_certificate": &schema.StringAttribute{
+				Description: "PEM encoded CA certificates trusted for directory_url besides the system roots, e.g. the root of a local test CA.",
+				Optional:    true,
+			},
+			"email": &schema.StringAttribute{
+				Description: "Contact email of the ACME account.",
+				Optional:    true,
+			},
+			"account_key_pem": &schema.StringAttribute{
+				Description: "PEM encoded private key of the ACME account, generated if it is not set.",
+				Optional:    true,
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"common_name": &schema.StringAttribute{
+				Description: "Common name of the certificate. E.g: www.example.com",
+				Required:    true,
+			},
+			"subject_alternative_names": &schema.ListAttribute{
+				Description: "DNS names covered by the certificate besides common_name. E.g: *.example.com",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+				},
+			},
+			"key_type": &schema.StringAttribute{
+				Description: `Algorithm of the private key of the certificate, the optional value is
+                                    RSA2048, RSA4096, ECDSA256, ECDSA384.
+                                    Default: ECDSA256`,
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(csrAlgorithmEcdsa256),
+				Validators: []validator.String{
+					stringvalidator.OneOf(csrAlgorithmRsa2048, csrAlgorithmRsa4096, csrAlgorithmEcdsa256, csrAlgorithmEcdsa384),
+				},
+			},
+			"renew_before_days": &schema.Int64Attribute{
+				Description: fmt.Sprintf("Renew the certificate if it expires within the number of days at plan time. Default: %d", defaultAcmeRenewBeforeDays),
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(defaultAcmeRenewBeforeDays),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"challenge": &schema.SingleNestedAttribute{
+				Description: "The hook solving the ACME challenges. The commands are run with the environment variables " +
+					"ACME_CHALLENGE_TYPE, ACME_DOMAIN, ACME_TOKEN, ACME_KEY_AUTHORIZATION, ACME_DNS_RECORD_NAME, ACME_DNS_RECORD_VALUE and ACME_HTTP_PATH.",
+				Required: true,
+				Attributes: map[string]schema.Attribute{
+					"type": &schema.StringAttribute{
+						Description: `Type of the challenge, the optional value is
+                                    dns-01: create the TXT record ACME_DNS_RECORD_NAME with the value ACME_DNS_RECORD_VALUE.
+                                    http-01: respond ACME_KEY_AUTHORIZATION at http://ACME_DOMAIN/ACME_HTTP_PATH.`,
+						Required: true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(acmeChallengeDns01, acmeChallengeHttp01),
+						},
+					},
+					"present_command": &schema.ListAttribute{
+						Description: "The command and its arguments making the challenge response reachable, e.g. a script creating the DNS record through the DNS provider.",
+						ElementType: types.StringType,
+						Required:    true,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+						},
+					},
+					"cleanup_command": &schema.ListAttribute{
+						Description: "The command and its arguments removing the challenge response, run even if the challenge fails.",
+						ElementType: types.StringType,
+						Optional:    true,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+						},
+					},
+					"propagation_wait_seconds": &schema.Int64Attribute{
+						Description: "Seconds to wait after present_command before the challenge is validated, e.g. for the DNS record to propagate.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+				},
+			},
+			"certificate_pem": &schema.StringAttribute{
+				Description: "PEM encoded certificate issued.",
+				Computed:    true,
+			},
+			"issuer_pem": &schema.StringAttribute{
+				Description: "PEM encoded CA certificates of certificate_pem.",
+				Computed:    true,
+			},
+			"private_key_pem": &schema.StringAttribute{
+				Description: "PEM encoded private key of certificate_pem.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"not_before": &schema.StringAttribute{
+				Description: "The time from which the certificate is valid, in RFC3339 format.",
+				Computed:    true,
+			},
+			"not_after": &schema.StringAttribute{
+				Description: "The time after which the certificate is expired, in RFC3339 format.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *acmeCertificateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(*providerData).client
+}
+
+func (r *acmeCertificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model acmeCertificateModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	if model.AccountKeyPem.IsUnknown() || model.AccountKeyPem.IsNull() {
+		accountKey, err := generatePrivateKey(csrAlgorithmEcdsa256)
+		if err == nil {
+			var accountKeyPem string
+			accountKeyPem, err = encodePrivateKey(accountKey)
+			model.AccountKeyPem = types.StringValue(accountKeyPem)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("[ACME ERROR] Fail to Generate Account Key", err.Error())
+			return
+		}
+	}
+
+	if err := r.issueCertificate(ctx, &model); err != nil {
+		resp.Diagnostics.AddError("[ACME ERROR] Fail to Issue Certificate", err.Error())
+		return
+	}
+
+	certificate := model.CertificatePem.ValueString() + model.IssuerPem.ValueString()
+	addCertificateResponse, err := r.client.AddCertificateV2(cdnetworksapi.AddCertificateV2Request{
+		Name:        model.Name.ValueStringPointer(),
+		Certificate: &certificate,
+		PrivateKey:  model.PrivateKeyPem.ValueStringPointer(),
+		Comment:     model.Comment.ValueStringPointer(),
+	})
+	if err == nil && *addCertificateResponse.Code != "0" {
+		err = errors.New(*addCertificateResponse.Message)
+	}
+	if err != nil {
+		// An error taints the resource, which is then replaced by issuing a
+		// new certificate. Save the certificate issued without
+		// certificate_id instead, and upload it by the next apply, see
+		// ModifyPlan.
+		resp.Diagnostics.AddWarning("[API ERROR] Failed to Add Certificate",
+			err.Error()+"\nThe certificate issued is saved without certificate_id, it is uploaded by the next apply.")
+		model.Id = types.StringNull()
+		resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+		return
+	}
+	model.Id = types.StringValue(*addCertificateResponse.CertificateId)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *acmeCertificateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state acmeCertificateModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// The certificate failed to be uploaded, see Create.
+	if state.Id.IsNull() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	queryCertificateInfoResponse, err := r.client.QueryCertificateInfo(state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Query Certificate", err.Error())
+		return
+	}
+	if *queryCertificateInfoResponse.Code == certificateNotFoundCode {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if *queryCertificateInfoResponse.Code != 0 {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Query Certificate", *queryCertificateInfoResponse.Message)
+		return
+	}
+	info := queryCertificateInfoResponse.QueryCertificateInfoResponseData
+	state.Name = types.StringPointerValue(info.Name)
+	state.Comment = types.StringPointerValue(info.Comment)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *acmeCertificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state acmeCertificateModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	plan.Id = state.Id
+	if plan.AccountKeyPem.IsUnknown() {
+		plan.AccountKeyPem = state.AccountKeyPem
+	}
+
+	// The certificate differs from state if it is to be renewed, it is unknown
+	// unless the one renewed by a failed apply is reused, see ModifyPlan.
+	renew := !plan.CertificatePem.Equal(state.CertificatePem)
+	if plan.CertificatePem.IsUnknown() {
+		if err := r.issueCertificate(ctx, &plan); err != nil {
+			resp.Diagnostics.AddError("[ACME ERROR] Fail to Renew Certificate", err.Error())
+			return
+		}
+	}
+
+	certificate := plan.CertificatePem.ValueString() + plan.IssuerPem.ValueString()
+	if state.Id.IsNull() {
+		// The certificate failed to be uploaded on create, see Create.
+		addCertificateResponse, err := r.client.AddCertificateV2(cdnetworksapi.AddCertificateV2Request{
+			Name:        plan.Name.ValueStringPointer(),
+			Certificate: &certificate,
+			PrivateKey:  plan.PrivateKeyPem.ValueStringPointer(),
+			Comment:     plan.Comment.ValueStringPointer(),
+		})
+		if err == nil && *addCertificateResponse.Code != "0" {
+			err = errors.New(*addCertificateResponse.Message)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Failed to Add Certificate", err.Error())
+			if renew {
+				resp.Diagnostics.Append(plan.recordIssued(ctx, resp.Private)...)
+			}
+			return
+		}
+		plan.Id = types.StringValue(*addCertificateResponse.CertificateId)
+	} else if renew || !plan.Name.Equal(state.Name) || !plan.Comment.Equal(state.Comment) {
+		updateCertificateResponse, err := r.client.UpdateCertificateV2(state.Id.ValueString(), cdnetworksapi.UpdateCertificateV2Request{
+			Name:        plan.Name.ValueStringPointer(),
+			Certificate: &certificate,
+			PrivateKey:  plan.PrivateKeyPem.ValueStringPointer(),
+			Comment:     plan.Comment.ValueStringPointer(),
+		})
+		if err == nil && *updateCertificateResponse.Code != "0" {
+			err = errors.New(*updateCertificateResponse.Message)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Failed to Update Certificate", err.Error())
+			if renew {
+				resp.Diagnostics.Append(plan.recordIssued(ctx, resp.Private)...)
+			}
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateStateKeyIssued, []byte("{}"))...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *acmeCertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state acmeCertificateModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || state.Id.IsNull() {
+		return
+	}
+
+	deleteCertificateResponse, err := r.client.DeleteCertificateV2(state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Failed to Del Certificate", err.Error())
+		return
+	}
+	if *deleteCertificateResponse.Code != "0" {
+		resp.Diagnostics.AddError("[API ERROR] Failed to Del Certificate", *deleteCertificateResponse.Message)
+		return
+	}
+}
+
+// ModifyPlan plans to renew the certificate if it expires within
+// renew_before_days, or the certificate to be issued is changed. The
+// certificate renewed by a failed apply is reused if it is recorded in the
+// private state. Otherwise the certificate of state is kept, and uploaded if
+// it failed to be uploaded on create.
+func (r *acmeCertificateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state *acmeCertificateModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan == nil || state == nil {
+		return
+	}
+
+	renew := !plan.CommonName.Equal(state.CommonName) ||
+		!plan.SubjectAlternativeNames.Equal(state.SubjectAlternativeNames) ||
+		!plan.KeyType.Equal(state.KeyType) ||
+		!plan.DirectoryUrl.Equal(state.DirectoryUrl)
+	if notAfter, ok := parseCertificateTime(state.NotAfter.ValueString()); !renew && ok && !plan.RenewBeforeDays.IsUnknown() {
+		if time.Now().AddDate(0, 0, int(plan.RenewBeforeDays.ValueInt64())).After(notAfter) {
+			renew = true
+			resp.Diagnostics.AddWarning("Certificate renewal",
+				fmt.Sprintf("The certificate %q expires at %s, within %d days, it will be renewed.",
+					state.Name.ValueString(), state.NotAfter.ValueString(), plan.RenewBeforeDays.ValueInt64()))
+		}
+	}
+
+	if renew {
+		plan.setCertificateUnknown()
+		_, diags := plan.reuseIssued(ctx, req.Private)
+		resp.Diagnostics.Append(diags...)
+	} else {
+		plan.copyCertificate(state)
+	}
+	if state.Id.IsNull() {
+		plan.Id = types.StringUnknown()
+	}
+	resp.Plan.Set(ctx, plan)
+}
+
+// issueCertificate obtains a certificate of the domains of model from the
+// ACME directory with a new private key, and sets the certificate attributes
+// of model.
+func (r *acmeCertificateResource) issueCertificate(ctx context.Context, model *acmeCertificateModel) error {
+	accountKey, err := parsePemPrivateKey(model.AccountKeyPem.ValueString())
+	if err != nil {
+		return fmt.Errorf("invalid account_key_pem: %w", err)
+	}
+	hook, err := model.Challenge.hook(ctx)
+	if err != nil {
+		return err
+	}
+	issuer, err := newAcmeIssuer(model.DirectoryUrl.ValueString(), model.DirectoryCaCertificate.ValueString(),
+		accountKey, model.Email.ValueString(), model.Challenge.Type.ValueString(), hook)
+	if err != nil {
+		return fmt.Errorf("invalid directory_ca_certificate: %w", err)
+	}
+
+	domains, err := model.domains(ctx)
+	if err != nil {
+		return err
+	}
+	privateKey, err := generatePrivateKey(model.KeyType.ValueString())
+	if err != nil {
+		return err
+	}
+	chain, err := issuer.issue(ctx, domains, privateKey)
+	if err != nil {
+		return err
+	}
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return fmt.Errorf("invalid certificate issued: %w", err)
+	}
+	privateKeyPem, err := encodePrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	var issuerPem strings.Builder
+	for _, der := range chain[1:] {
+		pem.Encode(&issuerPem, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+	model.CertificatePem = types.StringValue(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: chain[0]})))
+	model.IssuerPem = types.StringValue(issuerPem.String())
+	model.PrivateKeyPem = types.StringValue(privateKeyPem)
+	model.NotBefore = types.StringValue(leaf.NotBefore.UTC().Format(time.RFC3339))
+	model.NotAfter = types.StringValue(leaf.NotAfter.UTC().Format(time.RFC3339))
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -83,10 +84,14 @@ type antiHotlinkingConfigModel struct {
 	IpControlRules      []*ipControlRuleModel      `tfsdk:"ip_control_rule"`
 	RefererControlRules []*refererControlRuleModel `tfsdk:"referer_control_rule"`
 	UaControlRules      []*uaControlRuleModel      `tfsdk:"ua_control_rule"`
+	WaitForDeployment   types.Bool                 `tfsdk:"wait_for_deployment"`
+	DeploymentStatus    types.String               `tfsdk:"deployment_status"`
+	Timeouts            timeouts.Value             `tfsdk:"timeouts"`
 }
 
 type antiHotlinkingConfigResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
 }
 
 var (
@@ -104,7 +109,7 @@ func (r *antiHotlinkingConfigResource) Metadata(_ context.Context, req resource.
 	resp.TypeName = req.ProviderTypeName + "_anti_hotlinking_config"
 }
 
-func (r *antiHotlinkingConfigResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *antiHotlinkingConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: `Anti-theft chain configuration
                     note:
@@ -115,6 +120,8 @@ func (r *antiHotlinkingConfigResource) Schema(_ context.Context, req resource.Sc
 				Description: "Domain id",
 				Required:    true,
 			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
 		},
 		Blocks: map[string]schema.Block{
 			"ip_control_rule": &schema.ListNestedBlock{
@@ -451,6 +458,7 @@ When adding a new configuration item, the default is 10`,
 					},
 				},
 			},
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -459,7 +467,9 @@ func (r *antiHotlinkingConfigResource) Configure(ctx context.Context, req resour
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
 }
 
 func (r *antiHotlinkingConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -469,7 +479,10 @@ func (r *antiHotlinkingConfigResource) Create(ctx context.Context, req resource.
 		return
 	}
 
-	err := r.updateConfig(model)
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to update anti_hotlinking_config", err.Error())
 	}
@@ -483,12 +496,16 @@ func (r *antiHotlinkingConfigResource) Read(ctx context.Context, req resource.Re
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	err := r.updateModel(model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to query anti_hotlinking_config", err.Error())
 		return
 	}
 
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
 	resp.State.Set(ctx, &model)
 }
 
@@ -498,7 +515,11 @@ func (r *antiHotlinkingConfigResource) Update(ctx context.Context, req resource.
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.updateConfig(plan)
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to update anti_hotlinking_config", err.Error())
 		return
@@ -512,10 +533,14 @@ func (r *antiHotlinkingConfigResource) Delete(ctx context.Context, req resource.
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	model.IpControlRules = make([]*ipControlRuleModel, 0)
 	model.RefererControlRules = make([]*refererControlRuleModel, 0)
 	model.UaControlRules = make([]*uaControlRuleModel, 0)
-	err := r.updateConfig(model)
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to delete anti_hotlinking_config", err.Error())
 	}
@@ -654,7 +679,7 @@ func (r *antiHotlinkingConfigResource) ImportState(ctx context.Context, req reso
 	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
 }
 
-func (r *antiHotlinkingConfigResource) updateConfig(model *antiHotlinkingConfigModel) error {
+func (r *antiHotlinkingConfigResource) updateConfig(ctx context.Context, model *antiHotlinkingConfigModel) error {
 	rules := make([]*cdnetworksapi.VisitControlRule, 0)
 	if model.IpControlRules != nil {
 		for _, ruleModel := range model.IpControlRules {
@@ -785,34 +810,14 @@ func (r *antiHotlinkingConfigResource) updateConfig(model *antiHotlinkingConfigM
 	if err != nil {
 		return err
 	}
-	return utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
+	return err
 }
 
 func (r *antiHotlinkingConfigResource) updateModel(model *antiHotlinkingConfigModel) error {
-	ipRuleIndexMap := make(map[string][]int)
-	for i, rule := range model.IpControlRules {
-		list, ok := ipRuleIndexMap[rule.String()]
-		if !ok {
-			list = make([]int, 0)
-		}
-		ipRuleIndexMap[rule.String()] = append(list, i)
-	}
-	refererRuleIndexMap := make(map[string][]int)
-	for i, rule := range model.RefererControlRules {
-		list, ok := refererRuleIndexMap[rule.String()]
-		if !ok {
-			list = make([]int, 0)
-		}
-		refererRuleIndexMap[rule.String()] = append(list, i)
-	}
-	uaRuleIndexMap := make(map[string][]int)
-	for i, rule := range model.UaControlRules {
-		list, ok := uaRuleIndexMap[rule.String()]
-		if !ok {
-			list = make([]int, 0)
-		}
-		uaRuleIndexMap[rule.String()] = append(list, i)
-	}
+	priorIpRules := model.IpControlRules
+	priorRefererRules := model.RefererControlRules
+	priorUaRules := model.UaControlRules
 
 	queryControlConfigResponse, err := r.client.QueryControlConfig(model.DomainId.ValueString())
 	if err != nil {
@@ -940,68 +945,11 @@ func (r *antiHotlinkingConfigResource) updateModel(model *antiHotlinkingConfigMo
 	}
 
 	// Sort rules to align with .tf configuration file
-	ipControlRules := make([]*ipControlRuleModel, len(model.IpControlRules))
-	unmatchedIpRules := make([]*ipControlRuleModel, 0)
-	for i := 0; i < len(model.IpControlRules); i++ {
-		rule := model.IpControlRules[i]
-		list, ok := ipRuleIndexMap[rule.String()]
-		if ok && len(list) > 0 {
-			ipControlRules[list[0]] = rule
-			ipRuleIndexMap[rule.String()] = list[1:]
-		} else {
-			unmatchedIpRules = append(unmatchedIpRules, rule)
-		}
-	}
-	for i, j := 0, 0; i < len(ipControlRules) && j < len(unmatchedIpRules); i++ {
-		if ipControlRules[i] != nil {
-			continue
-		}
-		ipControlRules[i] = unmatchedIpRules[j]
-		j++
-	}
-	model.IpControlRules = ipControlRules
+	model.IpControlRules = alignToPrior(priorIpRules, model.IpControlRules)
 
-	refererControlRules := make([]*refererControlRuleModel, len(model.RefererControlRules))
-	unmatchedRefererRules := make([]*refererControlRuleModel, 0)
-	for i := 0; i < len(model.RefererControlRules); i++ {
-		rule := model.RefererControlRules[i]
-		list, ok := refererRuleIndexMap[rule.String()]
-		if ok && len(list) > 0 {
-			refererControlRules[list[0]] = rule
-			refererRuleIndexMap[rule.String()] = list[1:]
-		} else {
-			unmatchedRefererRules = append(unmatchedRefererRules, rule)
-		}
-	}
-	for i, j := 0, 0; i < len(refererControlRules) && j < len(unmatchedRefererRules); i++ {
-		if refererControlRules[i] != nil {
-			continue
-		}
-		refererControlRules[i] = unmatchedRefererRules[j]
-		j++
-	}
-	model.RefererControlRules = refererControlRules
+	model.RefererControlRules = alignToPrior(priorRefererRules, model.RefererControlRules)
 
-	uaControlRules := make([]*uaControlRuleModel, len(model.UaControlRules))
-	unmatchedUaRules := make([]*uaControlRuleModel, 0)
-	for i := 0; i < len(model.UaControlRules); i++ {
-		rule := model.UaControlRules[i]
-		list, ok := uaRuleIndexMap[rule.String()]
-		if ok && len(list) > 0 {
-			uaControlRules[list[0]] = rule
-			uaRuleIndexMap[rule.String()] = list[1:]
-		} else {
-			unmatchedUaRules = append(unmatchedUaRules, model.UaControlRules[i])
-		}
-	}
-	for i, j := 0, 0; i < len(uaControlRules) && j < len(unmatchedUaRules); i++ {
-		if uaControlRules[i] != nil {
-			continue
-		}
-		uaControlRules[i] = unmatchedUaRules[j]
-		j++
-	}
-	model.UaControlRules = uaControlRules
+	model.UaControlRules = alignToPrior(priorUaRules, model.UaControlRules)
 
 	return nil
 }
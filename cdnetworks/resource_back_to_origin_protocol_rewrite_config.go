@@ -4,24 +4,28 @@ import (
 	"context"
 	"errors"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
 )
 
 type backToOriginProtocolRewriteConfigModel struct {
-	DomainId types.String `tfsdk:"domain_id"`
-	Protocol types.String `tfsdk:"protocol"`
-	Port     types.String `tfsdk:"port"`
+	DomainId          types.String   `tfsdk:"domain_id"`
+	Protocol          types.String   `tfsdk:"protocol"`
+	Port              types.String   `tfsdk:"port"`
+	WaitForDeployment types.Bool     `tfsdk:"wait_for_deployment"`
+	DeploymentStatus  types.String   `tfsdk:"deployment_status"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
 }
 
 type backToOriginProtocolRewriteConfigResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
 }
 
 var (
@@ -39,7 +43,7 @@ func (r *backToOriginProtocolRewriteConfigResource) Metadata(_ context.Context,
 	resp.TypeName = req.ProviderTypeName + "_back_to_origin_protocol_rewrite_config"
 }
 
-func (r *backToOriginProtocolRewriteConfigResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *backToOriginProtocolRewriteConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Set the CDN back-to-origin protocol. By default, it follows the protocol requested by the client. If choose HTTPS-->HTTP, port 80 will be used by default; if choose HTTP-->HTTPS, port 443 will be used by default..",
 		Attributes: map[string]schema.Attribute{
@@ -47,6 +51,8 @@ func (r *backToOriginProtocolRewriteConfigResource) Schema(_ context.Context, re
 				Description: "Domain ID",
 				Required:    true,
 			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
 			"protocol": &schema.StringAttribute{
 				Description: "The specified protocol is either http or https.",
 				Required:    true,
@@ -60,6 +66,9 @@ func (r *backToOriginProtocolRewriteConfigResource) Schema(_ context.Context, re
 				Computed:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
 	}
 }
 
@@ -67,7 +76,9 @@ func (r *backToOriginProtocolRewriteConfigResource) Configure(ctx context.Contex
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
 }
 
 func (r *backToOriginProtocolRewriteConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -77,7 +88,10 @@ func (r *backToOriginProtocolRewriteConfigResource) Create(ctx context.Context,
 		return
 	}
 
-	err := r.updateConfig(model)
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to set back_to_origin_protocol_rewrite_config", err.Error())
 	}
@@ -91,6 +105,9 @@ func (r *backToOriginProtocolRewriteConfigResource) Read(ctx context.Context, re
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	queryBackToOriginRewriteConfigResponse, err := r.client.QueryBackToOriginRewriteConfig(model.DomainId.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to query back_to_origin_protocol_rewrite_config", err.Error())
@@ -109,6 +126,7 @@ func (r *backToOriginProtocolRewriteConfigResource) Read(ctx context.Context, re
 		} else {
 			model.Port = types.StringPointerValue(queryBackToOriginRewriteConfigResponse.BackToOriginRewriteRule.Port)
 		}
+		model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
 		resp.State.Set(ctx, model)
 	}
 }
@@ -119,7 +137,11 @@ func (r *backToOriginProtocolRewriteConfigResource) Update(ctx context.Context,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.updateConfig(plan)
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to set back_to_origin_protocol_rewrite_config", err.Error())
 		return
@@ -133,9 +155,13 @@ func (r *backToOriginProtocolRewriteConfigResource) Delete(ctx context.Context,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	model.Protocol = types.StringNull()
 	model.Port = types.StringNull()
-	err := r.updateConfig(model)
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to delete back_to_origin_protocol_rewrite_config", err.Error())
 	}
@@ -164,7 +190,7 @@ func (r *backToOriginProtocolRewriteConfigResource) ModifyPlan(ctx context.Conte
 	resp.Plan.Set(ctx, plan)
 }
 
-func (r *backToOriginProtocolRewriteConfigResource) updateConfig(model *backToOriginProtocolRewriteConfigModel) error {
+func (r *backToOriginProtocolRewriteConfigResource) updateConfig(ctx context.Context, model *backToOriginProtocolRewriteConfigModel) error {
 	if model == nil {
 		return errors.New("model is nil")
 	}
@@ -178,5 +204,6 @@ func (r *backToOriginProtocolRewriteConfigResource) updateConfig(model *backToOr
 	if err != nil {
 		return err
 	}
-	return utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
+	return err
 }
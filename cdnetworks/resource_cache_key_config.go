@@ -0,0 +1,251 @@
+package cdnetworks
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+type cacheKeyRuleModel struct {
+	PathPattern types.String `tfsdk:"path_pattern"`
+	IgnoreCase  types.Bool   `tfsdk:"ignore_case"`
+	HeaderName  types.String `tfsdk:"header_name"`
+}
+
+type cacheKeyConfigModel struct {
+	DomainId          types.String         `tfsdk:"domain_id"`
+	CacheKeyRules     []*cacheKeyRuleModel `tfsdk:"cache_key_rule"`
+	WaitForDeployment types.Bool           `tfsdk:"wait_for_deployment"`
+	DeploymentStatus  types.String         `tfsdk:"deployment_status"`
+	Timeouts          timeouts.Value       `tfsdk:"timeouts"`
+}
+
+type cacheKeyConfigResource struct {
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
+}
+
+var (
+	_ resource.Resource                = &cacheKeyConfigResource{}
+	_ resource.ResourceWithConfigure   = &cacheKeyConfigResource{}
+	_ resource.ResourceWithModifyPlan  = &cacheKeyConfigResource{}
+	_ resource.ResourceWithImportState = &cacheKeyConfigResource{}
+)
+
+func NewCacheKeyConfigResource() resource.Resource {
+	return &cacheKeyConfigResource{}
+}
+
+func (r *cacheKeyConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cache_key_config"
+}
+
+func (r *cacheKeyConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Cache key configuration. The value of the specified request headers will be added to the cache key, so that one copy is cached for each value of the headers, e.g. Accept-Language.`,
+		Attributes: map[string]schema.Attribute{
+			"domain_id": schema.StringAttribute{
+				Description: "Domain id",
+				Required:    true,
+			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"cache_key_rule": &schema.ListNestedBlock{
+				Description: `Cache key rule configuration`,
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"path_pattern": &schema.StringAttribute{
+							Description: "The url matching mode supports regularization. If all matches, the input parameters can be configured as: .*",
+							Required:    true,
+						},
+						"ignore_case": &schema.BoolAttribute{
+							Description: `Whether to ignore letter case when matching path_pattern.`,
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+						},
+						"header_name": &schema.StringAttribute{
+							Description: `The request header names to be added to the cache key. Multiple separated by semicolons, e.g. Accept-Language;Accept-Encoding`,
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9-]+(;[A-Za-z0-9-]+)*$`), "Must be header names separated by semicolons"),
+							},
+						},
+					},
+				},
+			},
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *cacheKeyConfigResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
+}
+
+func (r *cacheKeyConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *cacheKeyConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to set cache_key_config", err.Error())
+	}
+	resp.State.Set(ctx, model)
+}
+
+func (r *cacheKeyConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model *cacheKeyConfigModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateModel(model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to read cache_key_config", err.Error())
+		return
+	}
+
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
+	resp.State.Set(ctx, model)
+}
+
+func (r *cacheKeyConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan *cacheKeyConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to set cache_key_config", err.Error())
+		return
+	}
+	resp.State.Set(ctx, plan)
+}
+
+func (r *cacheKeyConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model *cacheKeyConfigModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	model.CacheKeyRules = make([]*cacheKeyRuleModel, 0)
+	err := r.updateConfig(ctx, model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to delete cache_key_config", err.Error())
+	}
+}
+
+func (r *cacheKeyConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan *cacheKeyConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan == nil {
+		return
+	}
+
+	if plan.CacheKeyRules == nil || len(plan.CacheKeyRules) == 0 {
+		resp.Diagnostics.AddError("[Validate Config]Invalid Config", "cache_key_rule is required")
+		return
+	}
+}
+
+func (r *cacheKeyConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
+}
+
+func (r *cacheKeyConfigResource) updateConfig(ctx context.Context, model *cacheKeyConfigModel) error {
+	rules := make([]*cdnetworksapi.CacheKeyRule, 0)
+	if model.CacheKeyRules != nil {
+		for _, ruleModel := range model.CacheKeyRules {
+			rule := &cdnetworksapi.CacheKeyRule{
+				PathPattern: ruleModel.PathPattern.ValueStringPointer(),
+				IgnoreCase:  ruleModel.IgnoreCase.ValueBoolPointer(),
+				HeaderName:  ruleModel.HeaderName.ValueStringPointer(),
+			}
+			rules = append(rules, rule)
+		}
+	}
+	updateCacheKeyConfigRequest := cdnetworksapi.UpdateCacheKeyConfigRequest{
+		CacheKeyRules: rules,
+	}
+	_, err := r.client.UpdateCacheKeyConfig(model.DomainId.ValueString(), updateCacheKeyConfigRequest)
+	if err != nil {
+		return err
+	}
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
+	return err
+}
+
+func (r *cacheKeyConfigResource) updateModel(model *cacheKeyConfigModel) error {
+	priorRules := model.CacheKeyRules
+
+	queryCacheKeyConfigResponse, err := r.client.QueryCacheKeyConfig(model.DomainId.ValueString())
+	if err != nil {
+		return err
+	}
+	model.CacheKeyRules = make([]*cacheKeyRuleModel, 0)
+	if queryCacheKeyConfigResponse.CacheKeyRules != nil {
+		for _, rule := range queryCacheKeyConfigResponse.CacheKeyRules {
+			ruleModel := &cacheKeyRuleModel{
+				PathPattern: types.StringPointerValue(rule.PathPattern),
+				IgnoreCase:  types.BoolPointerValue(rule.IgnoreCase),
+				HeaderName:  types.StringPointerValue(rule.HeaderName),
+			}
+			model.CacheKeyRules = append(model.CacheKeyRules, ruleModel)
+		}
+	}
+
+	// Sort cache_key_rule to align with rules in .tf configuration file
+	model.CacheKeyRules = alignToPrior(priorRules, model.CacheKeyRules)
+
+	return nil
+}
+
+func (rule *cacheKeyRuleModel) String() string {
+	values := []string{
+		rule.PathPattern.String(),
+		rule.IgnoreCase.String(),
+		rule.HeaderName.String(),
+	}
+	return strings.Join(values, "$$")
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -41,10 +42,15 @@ type cacheTimeBehaviorModel struct {
 type cacheTimeModel struct {
 	DomainId           types.String              `tfsdk:"domain_id"`
 	CacheTimeBehaviors []*cacheTimeBehaviorModel `tfsdk:"cache_time_behavior"`
+	PurgeOnChange      *purgeOnChangeModel       `tfsdk:"purge_on_change"`
+	WaitForDeployment  types.Bool                `tfsdk:"wait_for_deployment"`
+	DeploymentStatus   types.String              `tfsdk:"deployment_status"`
+	Timeouts           timeouts.Value            `tfsdk:"timeouts"`
 }
 
 type cacheTimeResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
 }
 
 var (
@@ -62,7 +68,7 @@ func (r *cacheTimeResource) Metadata(_ context.Context, req resource.MetadataReq
 	resp.TypeName = req.ProviderTypeName + "_cache_time"
 }
 
-func (r *cacheTimeResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *cacheTimeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: `This resource implementation modifies the domain name cache time configuration, realizes the custom cache function according to the customer's request. Node cache is divided into regular cache and query string URl cache, where you can set the cache time and ignore certain headers that affect the cache, and whether to cache empty files, etc., can the query string Url be set to multiple or to cache the Url after removing the question mark (increasing hit rate)`,
 		Attributes: map[string]schema.Attribute{
@@ -70,8 +76,11 @@ func (r *cacheTimeResource) Schema(_ context.Context, req resource.SchemaRequest
 				Description: "Domain id",
 				Required:    true,
 			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
 		},
 		Blocks: map[string]schema.Block{
+			"purge_on_change": purgeOnChangeBlock(),
 			"cache_time_behavior": &schema.ListNestedBlock{
 				Description: `Cache time configuration`,
 				NestedObject: schema.NestedBlockObject{
@@ -176,6 +185,7 @@ When adding a new configuration item, the default is 10`,
 					},
 				},
 			},
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -184,7 +194,9 @@ func (r *cacheTimeResource) Configure(ctx context.Context, req resource.Configur
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
 }
 
 func (r *cacheTimeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -194,7 +206,10 @@ func (r *cacheTimeResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	err := r.updateConfig(model)
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to set cache_time", err.Error())
 	}
@@ -208,12 +223,16 @@ func (r *cacheTimeResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	err := r.updateModel(model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to read_cache_time", err.Error())
 		return
 	}
 
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
 	resp.State.Set(ctx, model)
 }
 
@@ -223,7 +242,11 @@ func (r *cacheTimeResource) Update(ctx context.Context, req resource.UpdateReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.updateConfig(plan)
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to set cache_time", err.Error())
 		return
@@ -237,8 +260,14 @@ func (r *cacheTimeResource) Delete(ctx context.Context, req resource.DeleteReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	model.CacheTimeBehaviors = make([]*cacheTimeBehaviorModel, 0)
-	err := r.updateConfig(model)
+	// purge_on_change applies to the configuration changes only, not to destroy.
+	model.PurgeOnChange = nil
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to delete cache_time", err.Error())
 	}
@@ -266,6 +295,10 @@ func (r *cacheTimeResource) ModifyPlan(ctx context.Context, req resource.ModifyP
 			return
 		}
 	}
+	if err := plan.PurgeOnChange.check(); err != nil {
+		resp.Diagnostics.AddError("[Validate Config]Invalid Config", err.Error())
+		return
+	}
 	resp.Plan.Set(ctx, plan)
 }
 
@@ -273,7 +306,7 @@ func (r *cacheTimeResource) ImportState(ctx context.Context, req resource.Import
 	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
 }
 
-func (r *cacheTimeResource) updateConfig(model *cacheTimeModel) error {
+func (r *cacheTimeResource) updateConfig(ctx context.Context, model *cacheTimeModel) error {
 	behaviors := make([]*cdnetworksapi.CacheTimeBehavior, 0)
 	if model.CacheTimeBehaviors != nil {
 		for _, behaviorModel := range model.CacheTimeBehaviors {
@@ -308,18 +341,13 @@ func (r *cacheTimeResource) updateConfig(model *cacheTimeModel) error {
 	if err != nil {
 		return err
 	}
-	return utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	wait := shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment)
+	model.DeploymentStatus, err = waitAndPurge(ctx, r.client, model.DomainId.ValueString(), wait, model.PurgeOnChange)
+	return err
 }
 
 func (r *cacheTimeResource) updateModel(model *cacheTimeModel) error {
-	behaviorIndexMap := make(map[string][]int)
-	for i, behavior := range model.CacheTimeBehaviors {
-		list, ok := behaviorIndexMap[behavior.String()]
-		if !ok {
-			list = make([]int, 0)
-		}
-		behaviorIndexMap[behavior.String()] = append(list, i)
-	}
+	priorBehaviors := model.CacheTimeBehaviors
 
 	queryCacheTimeConfigResponse, err := r.client.QueryCacheTimeConfig(model.DomainId.ValueString())
 	if err != nil {
@@ -357,26 +385,7 @@ func (r *cacheTimeResource) updateModel(model *cacheTimeModel) error {
 	}
 
 	// Sort cache_time_behavior to align with rules in .tf configuration file
-	cacheTimeBehaviors := make([]*cacheTimeBehaviorModel, len(model.CacheTimeBehaviors))
-	unmatchedBehaviors := make([]*cacheTimeBehaviorModel, 0)
-	for i := 0; i < len(model.CacheTimeBehaviors); i++ {
-		behavior := model.CacheTimeBehaviors[i]
-		list, ok := behaviorIndexMap[behavior.String()]
-		if ok && len(list) > 0 {
-			cacheTimeBehaviors[list[0]] = behavior
-			behaviorIndexMap[behavior.String()] = list[1:]
-		} else {
-			unmatchedBehaviors = append(unmatchedBehaviors, behavior)
-		}
-	}
-	for i, j := 0, 0; i < len(cacheTimeBehaviors) && j < len(unmatchedBehaviors); i++ {
-		if cacheTimeBehaviors[i] != nil {
-			continue
-		}
-		cacheTimeBehaviors[i] = unmatchedBehaviors[j]
-		j++
-	}
-	model.CacheTimeBehaviors = cacheTimeBehaviors
+	model.CacheTimeBehaviors = alignToPrior(priorBehaviors, model.CacheTimeBehaviors)
 
 	return nil
 }
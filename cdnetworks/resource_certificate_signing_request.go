@@ -0,0 +1,285 @@
+package cdnetworks
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+const (
+	csrAlgorithmRsa2048  = "RSA2048"
+	csrAlgorithmRsa4096  = "RSA4096"
+	csrAlgorithmEcdsa256 = "ECDSA256"
+	csrAlgorithmEcdsa384 = "ECDSA384"
+	defaultCsrAlgorithm  = csrAlgorithmRsa2048
+)
+
+type certificateSigningRequestModel struct {
+	Id                      types.String   `tfsdk:"csr_id"`
+	Name                    types.String   `tfsdk:"name"`
+	CommonName              types.String   `tfsdk:"common_name"`
+	Organization            types.String   `tfsdk:"organization"`
+	OrganizationUnit        types.String   `tfsdk:"organization_unit"`
+	Locality                types.String   `tfsdk:"locality"`
+	State                   types.String   `tfsdk:"state"`
+	Country                 types.String   `tfsdk:"country"`
+	SubjectAlternativeNames types.List     `tfsdk:"subject_alternative_names"`
+	Algorithm               types.String   `tfsdk:"algorithm"`
+	CsrPem                  types.String   `tfsdk:"csr_pem"`
+	Timeouts                timeouts.Value `tfsdk:"timeouts"`
+}
+
+type certificateSigningRequestResource struct {
+	client *cdnetworksapi.Client
+}
+
+var (
+	_ resource.Resource                = &certificateSigningRequestResource{}
+	_ resource.ResourceWithConfigure   = &certificateSigningRequestResource{}
+	_ resource.ResourceWithImportState = &certificateSigningRequestResource{}
+)
+
+func NewCertificateSigningRequestResource() resource.Resource {
+	return &certificateSigningRequestResource{}
+}
+
+func (r *certificateSigningRequestResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certificate_signing_request"
+}
+
+func (r *certificateSigningRequestResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	subjectPlanModifiers := []planmodifier.String{
+		stringplanmodifier.RequiresReplace(),
+	}
+	resp.Schema = schema.Schema{
+		Description: "Generate a certificate signing request (CSR) at vendor, the private key is generated and kept by vendor. " +
+			"Submit csr_pem to a CA, and upload the signed certificate with csr_id of st-cdnetworks_ssl_certificate. " +
+			"Any change of the CSR generates a new one.",
+		Attributes: map[string]schema.Attribute{
+			"csr_id": &schema.StringAttribute{
+				Description: "CSR ID",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": &schema.StringAttribute{
+				Description:   "CSR name",
+				Required:      true,
+				PlanModifiers: subjectPlanModifiers,
+			},
+			"common_name": &schema.StringAttribute{
+				Description:   "Common name of the subject. E.g: www.example.com",
+				Required:      true,
+				PlanModifiers: subjectPlanModifiers,
+			},
+			"organization": &schema.StringAttribute{
+				Description:   "Organization of the subject",
+				Optional:      true,
+				PlanModifiers: subjectPlanModifiers,
+			},
+			"organization_unit": &schema.StringAttribute{
+				Description:   "Organization unit of the subject",
+				Optional:      true,
+				PlanModifiers: subjectPlanModifiers,
+			},
+			"locality": &schema.StringAttribute{
+				Description:   "City of the subject",
+				Optional:      true,
+				PlanModifiers: subjectPlanModifiers,
+			},
+			"state": &schema.StringAttribute{
+				Description:   "State or province of the subject",
+				Optional:      true,
+				PlanModifiers: subjectPlanModifiers,
+			},
+			"country": &schema.StringAttribute{
+				Description:   "Two-letter country code of the subject. E.g: US",
+				Optional:      true,
+				PlanModifiers: subjectPlanModifiers,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(2, 2),
+				},
+			},
+			"subject_alternative_names": &schema.ListAttribute{
+				Description: "DNS names covered by the certificate besides common_name. E.g: *.example.com",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"algorithm": &schema.StringAttribute{
+				Description: `Algorithm of the private key, the optional value is
+                                    RSA2048, RSA4096, ECDSA256, ECDSA384.
+                                    Default: RSA2048`,
+				Optional:      true,
+				Computed:      true,
+				Default:       stringdefault.StaticString(defaultCsrAlgorithm),
+				PlanModifiers: subjectPlanModifiers,
+				Validators: []validator.String{
+					stringvalidator.OneOf(csrAlgorithmRsa2048, csrAlgorithmRsa4096, csrAlgorithmEcdsa256, csrAlgorithmEcdsa384),
+				},
+			},
+			"csr_pem": &schema.StringAttribute{
+				Description: "PEM encoded CSR to be submitted to a CA.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *certificateSigningRequestResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(*providerData).client
+}
+
+func (r *certificateSigningRequestResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model certificateSigningRequestModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	addCsrRequest := cdnetworksapi.AddCsrRequest{
+		Name:             model.Name.ValueStringPointer(),
+		CommonName:       model.CommonName.ValueStringPointer(),
+		Organization:     model.Organization.ValueStringPointer(),
+		OrganizationUnit: model.OrganizationUnit.ValueStringPointer(),
+		Locality:         model.Locality.ValueStringPointer(),
+		State:            model.State.ValueStringPointer(),
+		Country:          model.Country.ValueStringPointer(),
+		Algorithm:        model.Algorithm.ValueStringPointer(),
+	}
+	if !model.SubjectAlternativeNames.IsNull() {
+		resp.Diagnostics.Append(model.SubjectAlternativeNames.ElementsAs(ctx, &addCsrRequest.SubjectAlternativeNames, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	addCsrResponse, err := r.client.AddCsr(addCsrRequest)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Add CSR", err.Error())
+		return
+	}
+	model.Id = types.StringPointerValue(addCsrResponse.CsrId)
+
+	queryCsrResponse, err := r.client.QueryCsr(model.Id.ValueString())
+	if err != nil {
+		// Save the CSR so that it is not leaked, csr_pem is refreshed later.
+		model.CsrPem = types.StringNull()
+		resp.State.Set(ctx, &model)
+		resp.Diagnostics.AddError("[API ERROR] Fail to Query CSR", err.Error())
+		return
+	}
+	model.CsrPem = types.StringPointerValue(queryCsrResponse.Csr)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *certificateSigningRequestResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state certificateSigningRequestModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	queryCsrResponse, err := r.client.QueryCsr(state.Id.ValueString())
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("[API ERROR] Fail to Query CSR", err.Error())
+		return
+	}
+
+	// Vendor may add common_name to the subject alternative names, they are
+	// refreshed only when the CSR is imported.
+	if state.Name.IsNull() && len(queryCsrResponse.SubjectAlternativeNames) > 0 {
+		sans, diags := stringsValue(ctx, queryCsrResponse.SubjectAlternativeNames)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.SubjectAlternativeNames = sans
+	}
+	state.Name = types.StringPointerValue(queryCsrResponse.Name)
+	state.CommonName = types.StringPointerValue(queryCsrResponse.CommonName)
+	state.Organization = types.StringPointerValue(queryCsrResponse.Organization)
+	state.OrganizationUnit = types.StringPointerValue(queryCsrResponse.OrganizationUnit)
+	state.Locality = types.StringPointerValue(queryCsrResponse.Locality)
+	state.State = types.StringPointerValue(queryCsrResponse.State)
+	state.Country = types.StringPointerValue(queryCsrResponse.Country)
+	if queryCsrResponse.Algorithm != nil {
+		state.Algorithm = types.StringValue(*queryCsrResponse.Algorithm)
+	}
+	state.CsrPem = types.StringPointerValue(queryCsrResponse.Csr)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *certificateSigningRequestResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All the attributes except timeouts require replacement.
+	var plan certificateSigningRequestModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *certificateSigningRequestResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state certificateSigningRequestModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	_, err := r.client.DeleteCsr(state.Id.ValueString())
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return
+		}
+		resp.Diagnostics.AddError("[API ERROR] Fail to Delete CSR", err.Error())
+		return
+	}
+}
+
+func (r *certificateSigningRequestResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("csr_id"), req, resp)
+}
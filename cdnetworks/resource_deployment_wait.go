@@ -0,0 +1,139 @@
+package cdnetworks
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+type deploymentWaitModel struct {
+	DomainIds types.Set      `tfsdk:"domain_ids"`
+	Triggers  types.Map      `tfsdk:"triggers"`
+	Statuses  types.Map      `tfsdk:"statuses"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
+}
+
+type deploymentWaitResource struct {
+	client *cdnetworksapi.Client
+}
+
+var (
+	_ resource.Resource              = &deploymentWaitResource{}
+	_ resource.ResourceWithConfigure = &deploymentWaitResource{}
+)
+
+func NewDeploymentWaitResource() resource.Resource {
+	return &deploymentWaitResource{}
+}
+
+func (r *deploymentWaitResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment_wait"
+}
+
+func (r *deploymentWaitResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Wait until the domains are deployed. Used together with wait_for_deployment = false of the config resources, so that the deployment is waited once after all the changes of the domains are submitted. It waits again whenever domain_ids or triggers changes. Destroying this resource does nothing.`,
+		Attributes: map[string]schema.Attribute{
+			"domain_ids": &schema.SetAttribute{
+				Description: "The ids of the domains to wait for.",
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"triggers": &schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will wait for the deployment again. E.g: the ids or deployment_status of the config resources.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"statuses": &schema.MapAttribute{
+				Description: "The deployment status of each domain, keyed by domain id.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *deploymentWaitResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(*providerData).client
+}
+
+func (r *deploymentWaitResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *deploymentWaitModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	r.waitForDomains(ctx, model, &resp.Diagnostics)
+	resp.State.Set(ctx, model)
+}
+
+func (r *deploymentWaitResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model *deploymentWaitModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.State.Set(ctx, model)
+}
+
+func (r *deploymentWaitResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan *deploymentWaitModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	r.waitForDomains(ctx, plan, &resp.Diagnostics)
+	resp.State.Set(ctx, plan)
+}
+
+func (r *deploymentWaitResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Nothing to delete.
+}
+
+// waitForDomains waits for the domains one by one, as they are deployed
+// concurrently by vendor, the total time is about the slowest one.
+func (r *deploymentWaitResource) waitForDomains(ctx context.Context, model *deploymentWaitModel, diags *diag.Diagnostics) {
+	domainIds := make([]string, 0)
+	model.DomainIds.ElementsAs(ctx, &domainIds, false)
+
+	statuses := make(map[string]string)
+	for _, domainId := range domainIds {
+		statuses[domainId] = deploymentStatusInProgress
+	}
+	for _, domainId := range domainIds {
+		err := utils.WaitForDomainDeployed(ctx, r.client, domainId)
+		if err != nil {
+			diags.AddError("[API ERROR] Fail to wait for deployment of domain "+domainId, err.Error())
+			break
+		}
+		statuses[domainId] = deploymentStatusDeployed
+	}
+	var d diag.Diagnostics
+	model.Statuses, d = types.MapValueFrom(ctx, types.StringType, statuses)
+	diags.Append(d...)
+}
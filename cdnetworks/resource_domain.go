@@ -0,0 +1,422 @@
+package cdnetworks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/common"
+	. "github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/model"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+// domainProduct describes a CDNetworks product, all products share the same
+// domain resource implementation.
+type domainProduct struct {
+	// Suffix of the resource type name, e.g. content_acceleration_domain.
+	typeName string
+	// Product name used in description and diagnostics.
+	name string
+	// Service type of the product returned by vendor, e.g. 1028.
+	serviceType string
+	// Whether config_form_id must be specified. The domain is created as the
+	// default product of the contract if config_form_id is not specified.
+	configFormIdRequired bool
+}
+
+var (
+	contentAccelerationProduct = domainProduct{
+		typeName:    "content_acceleration_domain",
+		name:        "Content Acceleration",
+		serviceType: "1028",
+	}
+	floodShieldProduct = domainProduct{
+		typeName:    "flood_shield_domain",
+		name:        "Flood Shield",
+		serviceType: "1551",
+	}
+	dynamicWebAccelerationProduct = domainProduct{
+		typeName:             "dynamic_web_acceleration_domain",
+		name:                 "Dynamic Web Acceleration",
+		serviceType:          "1115",
+		configFormIdRequired: true,
+	}
+	downloadAccelerationProduct = domainProduct{
+		typeName:             "download_acceleration_domain",
+		name:                 "Download Acceleration",
+		serviceType:          "1391",
+		configFormIdRequired: true,
+	}
+	mediaLiveProduct = domainProduct{
+		typeName:             "media_live_domain",
+		name:                 "Media Live",
+		serviceType:          "1348",
+		configFormIdRequired: true,
+	}
+	// Origin shield is enabled on top of the product of the contract, thus
+	// the service type is not checked.
+	originShieldProduct = domainProduct{
+		typeName: "shield_domain",
+		name:     "Origin Shield",
+	}
+)
+
+type domainResource struct {
+	client  *cdnetworksapi.Client
+	product domainProduct
+}
+
+var (
+	_ resource.Resource                = &domainResource{}
+	_ resource.ResourceWithConfigure   = &domainResource{}
+	_ resource.ResourceWithImportState = &domainResource{}
+	_ resource.ResourceWithModifyPlan  = &domainResource{}
+)
+
+func NewContentAccelerationDomainResource() resource.Resource {
+	return &domainResource{product: contentAccelerationProduct}
+}
+
+func NewFloodShieldDomainResource() resource.Resource {
+	return &domainResource{product: floodShieldProduct}
+}
+
+func NewDynamicWebAccelerationDomainResource() resource.Resource {
+	return &domainResource{product: dynamicWebAccelerationProduct}
+}
+
+func NewDownloadAccelerationDomainResource() resource.Resource {
+	return &domainResource{product: downloadAccelerationProduct}
+}
+
+func NewMediaLiveDomainResource() resource.Resource {
+	return &domainResource{product: mediaLiveProduct}
+}
+
+func (r *domainResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.product.typeName
+}
+
+func (r *domainResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = DomainSchema
+	resp.Schema.Description = fmt.Sprintf("This resource provides the configuration of %s (service type %s) domain", r.product.name, r.product.serviceType)
+	resp.Schema.Blocks = map[string]schema.Block{
+		"timeouts": timeoutsBlock(ctx),
+	}
+}
+
+func (r *domainResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(*providerData).client
+}
+
+func (r *domainResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *DomainResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	r.createDomain(ctx, model, func() diag.Diagnostics {
+		return resp.State.Set(ctx, model)
+	}, &resp.Diagnostics)
+}
+
+// createDomain creates the domain and waits until it is deployed, setState is
+// called to save the state as soon as the domain is created.
+func (r *domainResource) createDomain(ctx context.Context, model *DomainResourceModel, setState func() diag.Diagnostics, diags *diag.Diagnostics) {
+	// Add mutex lock to prevent concurrent call to API and overwritten occured.
+	// As BindControlGroup API might being called too fast and data overwritten.
+	mutex.Lock()
+	defer func() {
+		mutex.Unlock()
+	}()
+
+	addCdnDomainRequest := cdnetworksapi.AddCdnDomainRequest{
+		Version:           API_VERSION,
+		DomainName:        model.Domain.ValueStringPointer(),
+		ConfigFormId:      model.ConfigFormId.ValueStringPointer(),
+		AccelerateNoChina: model.AccelerateNoChina.ValueStringPointer(),
+		ContractId:        model.ContractId.ValueStringPointer(),
+		ItemId:            model.ItemId.ValueStringPointer(),
+		Comment:           model.Comment.ValueStringPointer(),
+		HeaderOfClientIp:  model.HeaderOfClientIp.ValueStringPointer(),
+		OriginConfig:      model.BuildApiOriginConfig(nil),
+	}
+
+	addCdnDomainResponse, err := r.client.AddCdnDomain(addCdnDomainRequest)
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Add "+r.product.name+" Domain", err.Error())
+		return
+	}
+
+	model.DomainId = types.StringValue(*addCdnDomainResponse.DomainId)
+	model.Status = types.StringValue("InProgress")
+
+	// Save state after cdn is created, prevent become orphan.
+	// But will prompt error for those field that required 'computed' but not inputted.
+	diags.Append(setState()...)
+
+	// Append newly added cdn domains to control_group, to bind to specific account.
+	if model.ControlGroup != nil {
+		err = common.BindCdnDomainToControlGroup(r.client, model)
+		if err != nil {
+			diags.AddError("[API ERROR] Fail to Bind Control Group", err.Error())
+			return
+		}
+	}
+
+	err = utils.WaitForDomainDeployed(ctx, r.client, model.DomainId.ValueString())
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Check DomainStatus", err.Error())
+		return
+	}
+
+	// Only trigger UpdateCdnDomain() when cache_host is not "".
+	if model.CacheHost.ValueString() != "" {
+		updateCdnDomainRequest := cdnetworksapi.UpdateCdnDomainRequest{
+			Version:    API_VERSION,
+			DomainName: model.Domain.ValueStringPointer(),
+			CacheHost:  model.CacheHost.ValueStringPointer(),
+		}
+		_, err := r.client.UpdateCdnDomain(model.DomainId.ValueString(), updateCdnDomainRequest)
+		if err != nil {
+			diags.AddError("[API ERROR] Fail to Update "+r.product.name+" Cache-host for Domain", err.Error())
+			return
+		}
+	}
+
+	// Required as copying computedFields from queryResponse.
+	queryCdnDomainResponse, err := r.client.QueryCdnDomain(model.DomainId.ValueString())
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Query "+r.product.name+" Domain", err.Error())
+		return
+	}
+	model.CopyComputedFields(&queryCdnDomainResponse)
+
+	diags.Append(setState()...)
+
+	// The product of the domain is decided by config_form_id, which cannot be
+	// resolved by the API before the domain is created. Warn only, the domain
+	// is usable and the config form may be mapped to another service type.
+	if serviceType := model.ServiceType.ValueString(); r.product.serviceType != "" && serviceType != "" && serviceType != r.product.serviceType {
+		diags.AddAttributeWarning(
+			path.Root("config_form_id"),
+			"Unexpected Service Type",
+			fmt.Sprintf("domain is created with service type %s, expected %s (%s), please check config_form_id", serviceType, r.product.serviceType, r.product.name),
+		)
+	}
+}
+
+func (r *domainResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model *DomainResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	r.readDomain(ctx, model, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *domainResource) readDomain(ctx context.Context, model *DomainResourceModel, diags *diag.Diagnostics) {
+	var domain string
+	if !model.DomainId.IsNull() {
+		domain = model.DomainId.ValueString()
+	} else {
+		domain = model.Domain.ValueString()
+	}
+
+	queryCdnDomain := func() error {
+		queryCdnDomainResponse, err := r.client.QueryCdnDomain(domain)
+		if err != nil {
+			if cdnErr, ok := err.(*cdnetworksapi.ErrorResponse); ok {
+				if cdnErr.ResponseCode == "WRONG_OPERATOR" {
+					if model.ControlGroup != nil {
+						diags.AddWarning("[Call API] Trying to bind "+r.product.name+" Domain to Control Group.", fmt.Sprintf("Domain: %s", model.Domain.ValueString()))
+						// Bind CDN domains to ControlGroup, in case previous bind action doesn't complete.
+						// Prevent error from Read(), Create() might failed to bind into controlGroup.
+						err = common.BindCdnDomainToControlGroup(r.client, model)
+						if err != nil {
+							return backoff.Permanent(fmt.Errorf("bind control group API error. err: %v", err))
+						}
+					}
+
+					// Retry for queryCdnDomain action
+					return cdnErr
+				}
+				return backoff.Permanent(fmt.Errorf("unexpected error code. code: %s err: %v", cdnErr.ResponseCode, err))
+			}
+			return backoff.Permanent(fmt.Errorf("queryCdnDomain API error, err: %v", err))
+		}
+
+		model.UpdateDomainFromApiConfig(ctx, &queryCdnDomainResponse)
+		return nil
+	}
+
+	err := backoff.Retry(queryCdnDomain, backoff.WithContext(backoff.NewExponentialBackOff(), ctx))
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Query "+r.product.name+" Domain", err.Error())
+	}
+}
+
+func (r *domainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan DomainResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	r.updateDomain(ctx, &plan, &state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.Set(ctx, plan)
+}
+
+func (r *domainResource) updateDomain(ctx context.Context, plan, state *DomainResourceModel, diags *diag.Diagnostics) {
+	plan.DomainId = state.DomainId
+
+	if state.Enabled.ValueBool() {
+		updateCdnDomainRequest := cdnetworksapi.UpdateCdnDomainRequest{
+			Version:          API_VERSION,
+			DomainName:       plan.Domain.ValueStringPointer(),
+			Comment:          plan.Comment.ValueStringPointer(),
+			CacheHost:        plan.CacheHost.ValueStringPointer(),
+			HeaderOfClientIp: plan.HeaderOfClientIp.ValueStringPointer(),
+			OriginConfig:     plan.BuildApiOriginConfig(state),
+		}
+		_, err := r.client.UpdateCdnDomain(plan.DomainId.ValueString(), updateCdnDomainRequest)
+		if err != nil {
+			diags.AddError("[API ERROR] Fail to Update "+r.product.name+" Domain", err.Error())
+			return
+		}
+	} else if plan.Enabled.Equal(state.Enabled) {
+		diags.AddError("[API ERROR] Update disabled domain is not Allowed", "")
+		return
+	}
+
+	var err error
+
+	if !plan.Enabled.Equal(state.Enabled) && !plan.Enabled.IsNull() {
+		if plan.Enabled.ValueBool() {
+			_, err = r.client.EnableDomain(plan.DomainId.ValueString())
+		} else {
+			_, err = r.client.DisableDomain(plan.DomainId.ValueString())
+		}
+		if err != nil {
+			diags.AddError("[API ERROR] Fail to Enable/Disable "+r.product.name+" Domain", err.Error())
+			return
+		}
+	}
+
+	err = utils.WaitForDomainDeployed(ctx, r.client, plan.DomainId.ValueString())
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Check DomainStatus", err.Error())
+		return
+	}
+
+	queryCdnDomainResponse, err := r.client.QueryCdnDomain(plan.DomainId.ValueString())
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Query "+r.product.name+" Domain", err.Error())
+		return
+	}
+	plan.CopyComputedFields(&queryCdnDomainResponse)
+}
+
+func (r *domainResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model *DomainResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	r.deleteDomain(ctx, model, &resp.Diagnostics)
+}
+
+func (r *domainResource) deleteDomain(ctx context.Context, model *DomainResourceModel, diags *diag.Diagnostics) {
+	_, err := r.client.DeleteApiDomain(model.DomainId.ValueString())
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Delete "+r.product.name+" Domain", err.Error())
+		return
+	}
+
+	err = utils.WaitForDomainDeleted(ctx, r.client, model.DomainId.ValueString())
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Check DomainStatus", err.Error())
+		return
+	}
+}
+
+func (r *domainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("domain"), req, resp)
+}
+
+func (r *domainResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan *DomainResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if plan == nil {
+		return
+	}
+	r.modifyDomainPlan(plan, req.State.Raw.IsNull(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Plan.Set(ctx, plan)
+}
+
+func (r *domainResource) modifyDomainPlan(plan *DomainResourceModel, creating bool, diags *diag.Diagnostics) {
+	plan.Fill()
+	err := plan.Check()
+	if err != nil {
+		diags.AddError("[Validate Config] Invalid Config", err.Error())
+		return
+	}
+	// config_form_id only takes effect on creation.
+	if !creating || plan.ConfigFormId.IsUnknown() {
+		return
+	}
+	if !plan.ConfigFormId.IsNull() && strings.TrimSpace(plan.ConfigFormId.ValueString()) == "" {
+		diags.AddAttributeError(
+			path.Root("config_form_id"),
+			"[Validate Config] Invalid Config",
+			"config_form_id must not be empty, remove it to create the domain as the default product of the contract",
+		)
+		return
+	}
+	if r.product.configFormIdRequired && plan.ConfigFormId.IsNull() {
+		diags.AddAttributeError(
+			path.Root("config_form_id"),
+			"[Validate Config] Invalid Config",
+			fmt.Sprintf("config_form_id is required for %s domain, please contact vendor for the config form id of service type %s", r.product.name, r.product.serviceType),
+		)
+	}
+}
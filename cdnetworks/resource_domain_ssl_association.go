@@ -2,23 +2,63 @@ package cdnetworks
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
 )
 
 type domainSslAssociationModel struct {
-	DomainId         types.String `tfsdk:"domain_id"`
-	UseSsl           types.Bool   `tfsdk:"use_ssl"`
-	SslCertificateId types.String `tfsdk:"ssl_certificate_id"`
+	DomainId            types.String   `tfsdk:"domain_id"`
+	UseSsl              types.Bool     `tfsdk:"use_ssl"`
+	UseForSni           types.Bool     `tfsdk:"use_for_sni"`
+	SslCertificateId    types.String   `tfsdk:"ssl_certificate_id"`
+	EccSslCertificateId types.String   `tfsdk:"ecc_ssl_certificate_id"`
+	WaitForDeployment   types.Bool     `tfsdk:"wait_for_deployment"`
+	DeploymentStatus    types.String   `tfsdk:"deployment_status"`
+	Timeouts            timeouts.Value `tfsdk:"timeouts"`
+}
+
+// ssl returns the HTTPS setting of the domain. The ECDSA certificate is sent
+// only if it is set, or it is removed from state to be unbound, as it is not
+// supported by all the domains.
+func (m *domainSslAssociationModel) ssl(state *domainSslAssociationModel) *cdnetworksapi.Ssl {
+	ssl := &cdnetworksapi.Ssl{
+		UseSsl:           m.UseSsl.ValueBoolPointer(),
+		UseForSni:        m.UseForSni.ValueBoolPointer(),
+		SslCertificateId: m.SslCertificateId.ValueStringPointer(),
+	}
+	if !m.EccSslCertificateId.IsNull() || (state != nil && !state.EccSslCertificateId.IsNull()) {
+		eccSslCertificateId := m.EccSslCertificateId.ValueString()
+		ssl.EccSslCertificateId = &eccSslCertificateId
+	}
+	return ssl
+}
+
+// certificateIds returns the known IDs of the certificates to be bound by the
+// attribute paths.
+func (m *domainSslAssociationModel) certificateIds() map[string]string {
+	ids := make(map[string]string)
+	if !m.SslCertificateId.IsNull() && !m.SslCertificateId.IsUnknown() {
+		ids["ssl_certificate_id"] = m.SslCertificateId.ValueString()
+	}
+	if !m.EccSslCertificateId.IsNull() && !m.EccSslCertificateId.IsUnknown() {
+		ids["ecc_ssl_certificate_id"] = m.EccSslCertificateId.ValueString()
+	}
+	return ids
 }
 
 type domainSslAssociationResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
 }
 
 var (
@@ -36,22 +76,39 @@ func (r *domainSslAssociationResource) Metadata(_ context.Context, req resource.
 	resp.TypeName = req.ProviderTypeName + "_domain_ssl_association"
 }
 
-func (r *domainSslAssociationResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *domainSslAssociationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "",
+		Description: "Bind SSL certificates to the domain and enable HTTPS. The certificates must cover the domain name, which is validated at plan time and before the domain is updated. " +
+			"Destroying this resource disables HTTPS of the domain.",
 		Attributes: map[string]schema.Attribute{
 			"domain_id": &schema.StringAttribute{
-				Description: "",
+				Description: "ID of the domain.",
 				Required:    true,
 			},
 			"use_ssl": &schema.BoolAttribute{
-				Description: "",
+				Description: "Enable HTTPS of the domain. ssl_certificate_id is required if it is true.",
 				Required:    true,
 			},
+			"use_for_sni": &schema.BoolAttribute{
+				Description: "Serve the certificates only to the clients sending the domain name by SNI. Default: false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
 			"ssl_certificate_id": &schema.StringAttribute{
-				Description: "",
+				Description: "ID of the certificate bound to the domain. It must be an RSA certificate if ecc_ssl_certificate_id is set.",
 				Optional:    true,
 			},
+			"ecc_ssl_certificate_id": &schema.StringAttribute{
+				Description: "ID of the ECDSA certificate bound together with ssl_certificate_id, the clients supporting ECDSA are served by it. " +
+					"Only available if dual certificates are supported by the domain.",
+				Optional: true,
+			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -60,7 +117,9 @@ func (r *domainSslAssociationResource) Configure(ctx context.Context, req resour
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
 }
 
 func (r *domainSslAssociationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -70,18 +129,23 @@ func (r *domainSslAssociationResource) Create(ctx context.Context, req resource.
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	r.checkCertificatesCoverDomain(model, true, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	updateCdnDomainRequest := cdnetworksapi.UpdateCdnDomainRequest{
-		Ssl: &cdnetworksapi.Ssl{
-			UseSsl:           model.UseSsl.ValueBoolPointer(),
-			SslCertificateId: model.SslCertificateId.ValueStringPointer(),
-		},
+		Ssl: model.ssl(nil),
 	}
 	_, err := r.client.UpdateCdnDomain(model.DomainId.ValueString(), updateCdnDomainRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Add DomainSslAssociation", err.Error())
 		return
 	}
-	err = utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Check DomainStatus", err.Error())
 		return
@@ -95,40 +159,62 @@ func (r *domainSslAssociationResource) Read(ctx context.Context, req resource.Re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	queryCdnDomainResponse, err := r.client.QueryCdnDomain(model.DomainId.ValueString())
 	if err != nil {
+		if utils.IsNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("[API ERROR] Fail to Query DomainSslAssociation", err.Error())
 		return
 	}
-	if queryCdnDomainResponse.Ssl != nil {
-		model.UseSsl = types.BoolPointerValue(queryCdnDomainResponse.Ssl.UseSsl)
-		model.SslCertificateId = types.StringPointerValue(queryCdnDomainResponse.Ssl.SslCertificateId)
-	} else {
-		model.UseSsl = types.BoolValue(false)
-		model.SslCertificateId = types.StringNull()
+	ssl := queryCdnDomainResponse.Ssl
+	if ssl == nil {
+		ssl = &cdnetworksapi.Ssl{}
+	}
+	model.UseSsl = types.BoolValue(ssl.UseSsl != nil && *ssl.UseSsl)
+	model.UseForSni = types.BoolValue(ssl.UseForSni != nil && *ssl.UseForSni)
+	// The certificates are kept by vendor after HTTPS is disabled, they are
+	// refreshed only if HTTPS is enabled, otherwise they are not in use.
+	if model.UseSsl.ValueBool() {
+		model.SslCertificateId = nonEmptyStringValue(ssl.SslCertificateId)
+		model.EccSslCertificateId = nonEmptyStringValue(ssl.EccSslCertificateId)
+	}
+	if model.DeploymentStatus.ValueString() != deploymentStatusDeployed {
+		model.DeploymentStatus = types.StringPointerValue(queryCdnDomainResponse.Status)
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
 }
 
 func (r *domainSslAssociationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan *domainSslAssociationModel
+	var plan, state *domainSslAssociationModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	r.checkCertificatesCoverDomain(plan, true, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	updateCdnDomainRequest := cdnetworksapi.UpdateCdnDomainRequest{
-		Ssl: &cdnetworksapi.Ssl{
-			UseSsl:           plan.UseSsl.ValueBoolPointer(),
-			SslCertificateId: plan.SslCertificateId.ValueStringPointer(),
-		},
+		Ssl: plan.ssl(state),
 	}
 	_, err := r.client.UpdateCdnDomain(plan.DomainId.ValueString(), updateCdnDomainRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Update DomainSslAssociation", err.Error())
 		return
 	}
-	err = utils.WaitForDomainDeployed(r.client, plan.DomainId.ValueString())
+	plan.DeploymentStatus, err = waitForDeployment(ctx, r.client, plan.DomainId.ValueString(), shouldWaitForDeployment(plan.WaitForDeployment, r.waitForDeployment))
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Check DomainStatus", err.Error())
 		return
@@ -144,6 +230,9 @@ func (r *domainSslAssociationResource) Delete(ctx context.Context, req resource.
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	useSsl := false
 	updateCdnDomainRequest := cdnetworksapi.UpdateCdnDomainRequest{
 		Ssl: &cdnetworksapi.Ssl{
@@ -155,7 +244,7 @@ func (r *domainSslAssociationResource) Delete(ctx context.Context, req resource.
 		resp.Diagnostics.AddError("[API ERROR] Fail to Delete DomainSslAssociation", err.Error())
 		return
 	}
-	err = utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Check DomainStatus", err.Error())
 		return
@@ -179,4 +268,63 @@ func (r *domainSslAssociationResource) ModifyPlan(ctx context.Context, req resou
 		resp.Diagnostics.AddError("[Validate Config] Invalid config", "`ssl_certificate_id` is required when `use_ssl` is true")
 		return
 	}
+	if !plan.EccSslCertificateId.IsNull() && plan.SslCertificateId.IsNull() {
+		resp.Diagnostics.AddError("[Validate Config] Invalid config", "`ssl_certificate_id` is required when `ecc_ssl_certificate_id` is set")
+		return
+	}
+	if !plan.EccSslCertificateId.IsNull() && !plan.EccSslCertificateId.IsUnknown() && plan.EccSslCertificateId.Equal(plan.SslCertificateId) {
+		resp.Diagnostics.AddAttributeError(path.Root("ecc_ssl_certificate_id"), "[Validate Config] Invalid config", "`ecc_ssl_certificate_id` must differ from `ssl_certificate_id`")
+		return
+	}
+
+	// The certificates may be changed in the same apply, the coverage is
+	// validated again before the domain is updated.
+	r.checkCertificatesCoverDomain(plan, false, &resp.Diagnostics)
+}
+
+// checkCertificatesCoverDomain validates that the certificates to be bound
+// cover the domain name. The uncovered certificates are reported as errors if
+// failOnUncovered, otherwise warnings. The validation is skipped if the IDs
+// are unknown, or HTTPS is disabled.
+func (r *domainSslAssociationResource) checkCertificatesCoverDomain(model *domainSslAssociationModel, failOnUncovered bool, diags *diag.Diagnostics) {
+	ids := model.certificateIds()
+	if !model.UseSsl.ValueBool() || model.DomainId.IsUnknown() || len(ids) == 0 {
+		return
+	}
+
+	queryCdnDomainResponse, err := r.client.QueryCdnDomain(model.DomainId.ValueString())
+	if err != nil || queryCdnDomainResponse.DomainName == nil {
+		diags.AddAttributeWarning(path.Root("domain_id"), "Fail to Query Domain",
+			fmt.Sprintf("The certificates are not validated against the domain %s: %v", model.DomainId.ValueString(), err))
+		return
+	}
+	domainName := *queryCdnDomainResponse.DomainName
+
+	for attribute, id := range ids {
+		dnsNames, err := queryCertificateDnsNames(r.client, id)
+		if err != nil {
+			diags.AddAttributeWarning(path.Root(attribute), "Fail to Query Certificate",
+				fmt.Sprintf("The certificate %s is not validated against the domain %s: %v", id, domainName, err))
+			continue
+		}
+		// Nothing to validate against.
+		if len(dnsNames) == 0 || certificateCoversDnsName(dnsNames, domainName) {
+			continue
+		}
+		summary := "[Validate Config] Certificate not covering domain"
+		detail := fmt.Sprintf("The certificate %s covers %s, but not the domain %s.", id, strings.Join(dnsNames, ", "), domainName)
+		if failOnUncovered {
+			diags.AddAttributeError(path.Root(attribute), summary, detail)
+		} else {
+			diags.AddAttributeWarning(path.Root(attribute), summary, detail)
+		}
+	}
+}
+
+// nonEmptyStringValue converts s to a string value, null if s is nil or empty.
+func nonEmptyStringValue(s *string) types.String {
+	if s == nil || *s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(*s)
 }
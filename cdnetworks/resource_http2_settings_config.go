@@ -3,6 +3,7 @@ package cdnetworks
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -12,7 +13,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
 )
 
@@ -22,12 +22,16 @@ var http2SettingAttributeTypes = map[string]attr.Type{
 }
 
 type http2SettingsConfigModel struct {
-	DomainId      types.String `tfsdk:"domain_id"`
-	Http2Settings types.Object `tfsdk:"http2_settings"`
+	DomainId          types.String   `tfsdk:"domain_id"`
+	Http2Settings     types.Object   `tfsdk:"http2_settings"`
+	WaitForDeployment types.Bool     `tfsdk:"wait_for_deployment"`
+	DeploymentStatus  types.String   `tfsdk:"deployment_status"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
 }
 
 type http2SettingsConfigResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
 }
 
 var (
@@ -44,7 +48,7 @@ func (r *http2SettingsConfigResource) Metadata(_ context.Context, req resource.M
 	resp.TypeName = req.ProviderTypeName + "_http2_settings_config"
 }
 
-func (r *http2SettingsConfigResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *http2SettingsConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "HTTP2.0 controls whether CDN uses http2.0 protocol to interact with the client, and Back-to-Origin protocol version controls whether to use http2.0 protocol Back-to-Origin. Some products do not support to configure http2.0 Back-to-Origin.",
 		Attributes: map[string]schema.Attribute{
@@ -52,6 +56,8 @@ func (r *http2SettingsConfigResource) Schema(_ context.Context, req resource.Sch
 				Description: "Domain ID",
 				Required:    true,
 			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
 			"http2_settings": &schema.SingleNestedAttribute{
 				Attributes: map[string]schema.Attribute{
 					"enable_http2": &schema.BoolAttribute{
@@ -76,6 +82,9 @@ func (r *http2SettingsConfigResource) Schema(_ context.Context, req resource.Sch
 				Required: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
 	}
 }
 
@@ -83,7 +92,9 @@ func (r *http2SettingsConfigResource) Configure(ctx context.Context, req resourc
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
 }
 
 func (r *http2SettingsConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -93,7 +104,10 @@ func (r *http2SettingsConfigResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
-	err := r.updateConfig(model)
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to update http2_setting_config", err.Error())
 	}
@@ -107,6 +121,9 @@ func (r *http2SettingsConfigResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	queryHttp2SettingsConfigResponse, err := r.client.QueryHttp2SettingsConfig(model.DomainId.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to query http2_setting_config", err.Error())
@@ -120,6 +137,7 @@ func (r *http2SettingsConfigResource) Read(ctx context.Context, req resource.Rea
 			"back_to_origin_protocol": types.StringPointerValue(queryHttp2SettingsConfigResponse.Http2Setting.BackToOriginProtocol),
 		})
 	}
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
 	resp.State.Set(ctx, model)
 }
 
@@ -129,7 +147,11 @@ func (r *http2SettingsConfigResource) Update(ctx context.Context, req resource.U
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.updateConfig(plan)
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to update http2_settings_config", err.Error())
 		return
@@ -143,8 +165,12 @@ func (r *http2SettingsConfigResource) Delete(ctx context.Context, req resource.D
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	model.Http2Settings = types.ObjectNull(http2SettingAttributeTypes)
-	err := r.updateConfig(model)
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to delete http2_setting_config", err.Error())
 	}
@@ -154,7 +180,7 @@ func (r *http2SettingsConfigResource) ImportState(ctx context.Context, req resou
 	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
 }
 
-func (r *http2SettingsConfigResource) updateConfig(model *http2SettingsConfigModel) error {
+func (r *http2SettingsConfigResource) updateConfig(ctx context.Context, model *http2SettingsConfigModel) error {
 	setting := &cdnetworksapi.Http2Setting{}
 	for k, v := range model.Http2Settings.Attributes() {
 		if k == "enable_http2" && !v.IsNull() {
@@ -170,5 +196,6 @@ func (r *http2SettingsConfigResource) updateConfig(model *http2SettingsConfigMod
 	if err != nil {
 		return err
 	}
-	return utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
+	return err
 }
@@ -3,6 +3,7 @@ package cdnetworks
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -10,7 +11,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
 )
 
@@ -22,16 +22,22 @@ type httpCodeCacheRuleModel struct {
 type httpCodeCacheConfigModel struct {
 	DomainId           types.String              `tfsdk:"domain_id"`
 	HttpCodeCacheRules []*httpCodeCacheRuleModel `tfsdk:"http_code_cache_rule"`
+	PurgeOnChange      *purgeOnChangeModel       `tfsdk:"purge_on_change"`
+	WaitForDeployment  types.Bool                `tfsdk:"wait_for_deployment"`
+	DeploymentStatus   types.String              `tfsdk:"deployment_status"`
+	Timeouts           timeouts.Value            `tfsdk:"timeouts"`
 }
 
 type httpCodeCacheConfigResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
 }
 
 var (
 	_ resource.Resource                = &httpCodeCacheConfigResource{}
 	_ resource.ResourceWithConfigure   = &httpCodeCacheConfigResource{}
 	_ resource.ResourceWithImportState = &httpCodeCacheConfigResource{}
+	_ resource.ResourceWithModifyPlan  = &httpCodeCacheConfigResource{}
 )
 
 func NewHttpCodeCacheConfigResource() resource.Resource {
@@ -42,7 +48,7 @@ func (r *httpCodeCacheConfigResource) Metadata(_ context.Context, req resource.M
 	resp.TypeName = req.ProviderTypeName + "_http_code_cache_config"
 }
 
-func (r *httpCodeCacheConfigResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *httpCodeCacheConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: `Http Code Cache Configuration`,
 		Attributes: map[string]schema.Attribute{
@@ -50,8 +56,11 @@ func (r *httpCodeCacheConfigResource) Schema(_ context.Context, req resource.Sch
 				Description: "Domain id",
 				Required:    true,
 			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
 		},
 		Blocks: map[string]schema.Block{
+			"purge_on_change": purgeOnChangeBlock(),
 			"http_code_cache_rule": &schema.ListNestedBlock{
 				Description: `State Code Caching Rule Configuration, parent node
 1. When you need to set state code caching rules, this must be filled in.
@@ -73,6 +82,7 @@ func (r *httpCodeCacheConfigResource) Schema(_ context.Context, req resource.Sch
 					},
 				},
 			},
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -81,7 +91,9 @@ func (r *httpCodeCacheConfigResource) Configure(ctx context.Context, req resourc
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
 }
 
 func (r *httpCodeCacheConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -91,7 +103,10 @@ func (r *httpCodeCacheConfigResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
-	err := r.updateConfig(model)
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to set http_code_cache", err.Error())
 	}
@@ -108,6 +123,9 @@ func (r *httpCodeCacheConfigResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	queryHttpCodeCacheConfigResponse, err := r.client.QueryHttpCodeCacheConfig(model.DomainId.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to query http_code_cache", err.Error())
@@ -127,6 +145,7 @@ func (r *httpCodeCacheConfigResource) Read(ctx context.Context, req resource.Rea
 			model.HttpCodeCacheRules = append(model.HttpCodeCacheRules, ruleModel)
 		}
 	}
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
 	resp.State.Set(ctx, model)
 }
 
@@ -136,7 +155,11 @@ func (r *httpCodeCacheConfigResource) Update(ctx context.Context, req resource.U
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.updateConfig(plan)
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to set http_code_cache", err.Error())
 		return
@@ -150,18 +173,41 @@ func (r *httpCodeCacheConfigResource) Delete(ctx context.Context, req resource.D
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	model.HttpCodeCacheRules = make([]*httpCodeCacheRuleModel, 0)
-	err := r.updateConfig(model)
+	// purge_on_change applies to the configuration changes only, not to destroy.
+	model.PurgeOnChange = nil
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to delete http_code_cache", err.Error())
 	}
 }
 
+func (r *httpCodeCacheConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan *httpCodeCacheConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan == nil {
+		return
+	}
+
+	if err := plan.PurgeOnChange.check(); err != nil {
+		resp.Diagnostics.AddError("[Validate Config]Invalid Config", err.Error())
+		return
+	}
+}
+
 func (r *httpCodeCacheConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
 }
 
-func (r *httpCodeCacheConfigResource) updateConfig(model *httpCodeCacheConfigModel) error {
+func (r *httpCodeCacheConfigResource) updateConfig(ctx context.Context, model *httpCodeCacheConfigModel) error {
 	rules := make([]*cdnetworksapi.HttpCodeCacheRule, 0)
 	if model.HttpCodeCacheRules != nil {
 		for _, ruleModel := range model.HttpCodeCacheRules {
@@ -179,5 +225,7 @@ func (r *httpCodeCacheConfigResource) updateConfig(model *httpCodeCacheConfigMod
 	if err != nil {
 		return err
 	}
-	return utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	wait := shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment)
+	model.DeploymentStatus, err = waitAndPurge(ctx, r.client, model.DomainId.ValueString(), wait, model.PurgeOnChange)
+	return err
 }
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -38,13 +39,17 @@ type headerRuleModel struct {
 }
 
 type httpHeaderConfigModel struct {
-	DomainId  types.String       `tfsdk:"domain_id"`
-	HeaderIds types.Map          `tfsdk:"header_ids"`
-	Rules     []*headerRuleModel `tfsdk:"header_rule"`
+	DomainId          types.String       `tfsdk:"domain_id"`
+	HeaderIds         types.Map          `tfsdk:"header_ids"`
+	Rules             []*headerRuleModel `tfsdk:"header_rule"`
+	WaitForDeployment types.Bool         `tfsdk:"wait_for_deployment"`
+	DeploymentStatus  types.String       `tfsdk:"deployment_status"`
+	Timeouts          timeouts.Value     `tfsdk:"timeouts"`
 }
 
 type httpHeaderConfigResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
 }
 
 var (
@@ -62,7 +67,7 @@ func (r *httpHeaderConfigResource) Metadata(_ context.Context, req resource.Meta
 	resp.TypeName = req.ProviderTypeName + "_http_header_config"
 }
 
-func (r *httpHeaderConfigResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *httpHeaderConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Http header configuration",
 		Attributes: map[string]schema.Attribute{
@@ -70,6 +75,8 @@ func (r *httpHeaderConfigResource) Schema(_ context.Context, req resource.Schema
 				Description: "Domain ID",
 				Required:    true,
 			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
 			"header_ids": &schema.MapAttribute{
 				ElementType: types.Int64Type,
 				Optional:    false,
@@ -210,6 +217,7 @@ func (r *httpHeaderConfigResource) Schema(_ context.Context, req resource.Schema
 					},
 				},
 			},
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -218,7 +226,9 @@ func (r *httpHeaderConfigResource) Configure(ctx context.Context, req resource.C
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
 }
 
 func (r *httpHeaderConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -228,6 +238,9 @@ func (r *httpHeaderConfigResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	// Vendor will add their own headers.
 	// But since the API is PUT method, we need to get the headers
 	// that are already present, to prevent overwriting of existing headers
@@ -238,7 +251,7 @@ func (r *httpHeaderConfigResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	err = r.updateConfig(&vendorSpecificModel, nil)
+	err = r.updateConfig(ctx, &vendorSpecificModel, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to update http header", err.Error())
 		return
@@ -261,12 +274,16 @@ func (r *httpHeaderConfigResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	err := r.readModel(model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to read_http_header_config", err.Error())
 		return
 	}
 
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
 	resp.State.Set(ctx, model)
 }
 
@@ -282,6 +299,9 @@ func (r *httpHeaderConfigResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	stateHttpHeaders := []string{}
 	planHttpHeaders := []string{}
 	for _, rule := range state.Rules {
@@ -317,7 +337,7 @@ func (r *httpHeaderConfigResource) Update(ctx context.Context, req resource.Upda
 	// Temporarily set it to the header ids of the state
 	plan.HeaderIds = state.HeaderIds
 
-	err := r.updateConfig(plan, deletedHeaders.ToSlice())
+	err := r.updateConfig(ctx, plan, deletedHeaders.ToSlice())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to update http header config", err.Error())
 		return
@@ -349,13 +369,16 @@ func (r *httpHeaderConfigResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	// During deletion, only the data-id needs to be passed in.
 	deletedRules := []string{}
 	for _, rule := range model.Rules {
 		deletedRules = append(deletedRules, rule.HeaderName.ValueString())
 	}
 
-	err := r.updateConfig(model, deletedRules)
+	err := r.updateConfig(ctx, model, deletedRules)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to delete http head configs", err.Error())
 	}
@@ -435,7 +458,7 @@ func (r *httpHeaderConfigResource) ImportState(ctx context.Context, req resource
 	resp.State.Set(ctx, model)
 }
 
-func (r *httpHeaderConfigResource) updateConfig(model *httpHeaderConfigModel, deletedHeaders []string) error {
+func (r *httpHeaderConfigResource) updateConfig(ctx context.Context, model *httpHeaderConfigModel, deletedHeaders []string) error {
 	headerIds := make(map[string]types.Int64)
 
 	if !model.HeaderIds.IsNull() && !model.HeaderIds.IsUnknown() {
@@ -485,7 +508,8 @@ func (r *httpHeaderConfigResource) updateConfig(model *httpHeaderConfigModel, de
 	if err != nil {
 		return err
 	}
-	return utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
+	return err
 }
 
 // Appends the vendor's headers after the headers defined in the Terraform plan
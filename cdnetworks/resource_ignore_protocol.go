@@ -4,12 +4,12 @@ import (
 	"context"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
 )
 
@@ -22,10 +22,15 @@ type ignoreProtocolRuleModel struct {
 type ignoreProtocolModel struct {
 	DomainId            types.String               `tfsdk:"domain_id"`
 	IgnoreProtocolRules []*ignoreProtocolRuleModel `tfsdk:"ignore_protocol_rule"`
+	PurgeOnChange       *purgeOnChangeModel        `tfsdk:"purge_on_change"`
+	WaitForDeployment   types.Bool                 `tfsdk:"wait_for_deployment"`
+	DeploymentStatus    types.String               `tfsdk:"deployment_status"`
+	Timeouts            timeouts.Value             `tfsdk:"timeouts"`
 }
 
 type ignoreProtocolResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
 }
 
 var (
@@ -43,7 +48,7 @@ func (r *ignoreProtocolResource) Metadata(_ context.Context, req resource.Metada
 	resp.TypeName = req.ProviderTypeName + "_ignore_protocol"
 }
 
-func (r *ignoreProtocolResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *ignoreProtocolResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: `Ignore protocol caching and push configuration, parent tags
 1. This must be filled when protocol cache and push configuration need to be ignored
@@ -53,8 +58,11 @@ func (r *ignoreProtocolResource) Schema(_ context.Context, req resource.SchemaRe
 				Description: "Domain id",
 				Required:    true,
 			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
 		},
 		Blocks: map[string]schema.Block{
+			"purge_on_change": purgeOnChangeBlock(),
 			"ignore_protocol_rule": &schema.ListNestedBlock{
 				Description: `Ignore protocol configuration`,
 				NestedObject: schema.NestedBlockObject{
@@ -81,6 +89,7 @@ Note:
 					},
 				},
 			},
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -89,7 +98,9 @@ func (r *ignoreProtocolResource) Configure(ctx context.Context, req resource.Con
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
 }
 
 func (r *ignoreProtocolResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -99,7 +110,10 @@ func (r *ignoreProtocolResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	err := r.updateConfig(model)
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to set ignore protocol", err.Error())
 	}
@@ -113,12 +127,16 @@ func (r *ignoreProtocolResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	err := r.updateModel(model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to read ignore protocol", err.Error())
 		return
 	}
 
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
 	resp.State.Set(ctx, model)
 }
 
@@ -128,7 +146,11 @@ func (r *ignoreProtocolResource) Update(ctx context.Context, req resource.Update
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.updateConfig(plan)
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to set ignore protocol", err.Error())
 		return
@@ -142,8 +164,14 @@ func (r *ignoreProtocolResource) Delete(ctx context.Context, req resource.Delete
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	model.IgnoreProtocolRules = make([]*ignoreProtocolRuleModel, 0)
-	err := r.updateConfig(model)
+	// purge_on_change applies to the configuration changes only, not to destroy.
+	model.PurgeOnChange = nil
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to delete ignore protocol", err.Error())
 	}
@@ -172,6 +200,10 @@ func (r *ignoreProtocolResource) ModifyPlan(ctx context.Context, req resource.Mo
 		}
 	}
 
+	if err := plan.PurgeOnChange.check(); err != nil {
+		resp.Diagnostics.AddError("[Validate Config]Invalid Config", err.Error())
+		return
+	}
 	resp.Plan.Set(ctx, plan)
 }
 
@@ -179,7 +211,7 @@ func (r *ignoreProtocolResource) ImportState(ctx context.Context, req resource.I
 	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
 }
 
-func (r *ignoreProtocolResource) updateConfig(model *ignoreProtocolModel) error {
+func (r *ignoreProtocolResource) updateConfig(ctx context.Context, model *ignoreProtocolModel) error {
 	rules := make([]*cdnetworksapi.IgnoreProtocolRule, 0)
 	if model.IgnoreProtocolRules != nil {
 		for _, ruleModel := range model.IgnoreProtocolRules {
@@ -198,18 +230,13 @@ func (r *ignoreProtocolResource) updateConfig(model *ignoreProtocolModel) error
 	if err != nil {
 		return err
 	}
-	return utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	wait := shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment)
+	model.DeploymentStatus, err = waitAndPurge(ctx, r.client, model.DomainId.ValueString(), wait, model.PurgeOnChange)
+	return err
 }
 
 func (r *ignoreProtocolResource) updateModel(model *ignoreProtocolModel) error {
-	ruleIndexMap := make(map[string][]int)
-	for i, rule := range model.IgnoreProtocolRules {
-		list, ok := ruleIndexMap[rule.String()]
-		if !ok {
-			list = make([]int, 0)
-		}
-		ruleIndexMap[rule.String()] = append(list, i)
-	}
+	priorRules := model.IgnoreProtocolRules
 
 	queryIgnoreProtocolResponse, err := r.client.QueryIgnoreProtocol(model.DomainId.ValueString())
 	if err != nil {
@@ -228,26 +255,7 @@ func (r *ignoreProtocolResource) updateModel(model *ignoreProtocolModel) error {
 	}
 
 	// Sort rules to align with .tf configuration file
-	ignoreProtocolRules := make([]*ignoreProtocolRuleModel, len(model.IgnoreProtocolRules))
-	unmatchedIgnoreProtocolRules := make([]*ignoreProtocolRuleModel, 0)
-	for i := 0; i < len(model.IgnoreProtocolRules); i++ {
-		rule := model.IgnoreProtocolRules[i]
-		list, ok := ruleIndexMap[rule.String()]
-		if ok && len(list) > 0 {
-			ignoreProtocolRules[list[0]] = rule
-			ruleIndexMap[rule.String()] = list[1:]
-		} else {
-			unmatchedIgnoreProtocolRules = append(unmatchedIgnoreProtocolRules, rule)
-		}
-	}
-	for i, j := 0, 0; i < len(ignoreProtocolRules) && j < len(unmatchedIgnoreProtocolRules); i++ {
-		if ignoreProtocolRules[i] != nil {
-			continue
-		}
-		ignoreProtocolRules[i] = unmatchedIgnoreProtocolRules[j]
-		j++
-	}
-	model.IgnoreProtocolRules = ignoreProtocolRules
+	model.IgnoreProtocolRules = alignToPrior(priorRules, model.IgnoreProtocolRules)
 
 	return nil
 }
@@ -2,20 +2,20 @@ package cdnetworks
 
 import (
 	"context"
-	"errors"
-	"time"
 
-	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
 )
 
 type ipv6ResourceModel struct {
-	DomainId   types.String `tfsdk:"domain_id"`
-	EnableIpv6 types.Bool   `tfsdk:"enable_ipv6"`
+	DomainId   types.String   `tfsdk:"domain_id"`
+	EnableIpv6 types.Bool     `tfsdk:"enable_ipv6"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
 }
 
 type ipv6Resource struct {
@@ -35,7 +35,7 @@ func (r *ipv6Resource) Metadata(_ context.Context, req resource.MetadataRequest,
 	resp.TypeName = req.ProviderTypeName + "_ipv6_config"
 }
 
-func (r *ipv6Resource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *ipv6Resource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Update DNS region IP version, available value: 'V6'",
 		Attributes: map[string]schema.Attribute{
@@ -48,6 +48,9 @@ func (r *ipv6Resource) Schema(_ context.Context, req resource.SchemaRequest, res
 				Required:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
 	}
 }
 
@@ -55,7 +58,7 @@ func (r *ipv6Resource) Configure(ctx context.Context, req resource.ConfigureRequ
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 func (r *ipv6Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -65,6 +68,9 @@ func (r *ipv6Resource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	// By Default, IPv4 is enabled
 	ipVersions := []string{"V4"}
 	if model.EnableIpv6.ValueBool() {
@@ -84,11 +90,12 @@ func (r *ipv6Resource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	if r.waitForIPv6Config(model) {
-		resp.State.Set(ctx, &model)
-	} else {
-		resp.Diagnostics.AddError("[API ERROR] Failed to Add IPv6", "Timeout")
+	err = utils.WaitForIPv6Configured(ctx, r.client, model.DomainId.ValueString(), model.EnableIpv6.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Failed to Add IPv6", err.Error())
+		return
 	}
+	resp.State.Set(ctx, &model)
 }
 
 func (r *ipv6Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -98,6 +105,9 @@ func (r *ipv6Resource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	queryIPv6Response, err := r.client.QueryIPv6Config(state.DomainId.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Query IPv6", err.Error())
@@ -120,6 +130,9 @@ func (r *ipv6Resource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	ipVersions := []string{"V4"}
 	if plan.EnableIpv6.ValueBool() {
 		ipVersions = append(ipVersions, "V6")
@@ -138,13 +151,15 @@ func (r *ipv6Resource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	if r.waitForIPv6Config(plan) {
-		state.DomainId = plan.DomainId
-		state.EnableIpv6 = plan.EnableIpv6
-		resp.State.Set(ctx, state)
-	} else {
-		resp.Diagnostics.AddError("[API ERROR] Failed to Add IPv6", "Timeout")
+	err = utils.WaitForIPv6Configured(ctx, r.client, plan.DomainId.ValueString(), plan.EnableIpv6.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Failed to Update IPv6", err.Error())
+		return
 	}
+	state.DomainId = plan.DomainId
+	state.EnableIpv6 = plan.EnableIpv6
+	state.Timeouts = plan.Timeouts
+	resp.State.Set(ctx, state)
 }
 
 func (r *ipv6Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -155,6 +170,9 @@ func (r *ipv6Resource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	// Due to only have Update() func, force it revert to ipv4 only.
 	deleteIPv6Response, err := r.client.UpdateIPv6Config(model.DomainId.ValueString(), cdnetworksapi.UpdateIPv6ConfigRequest{
 		IpVersion: []string{"V4"},
@@ -169,25 +187,3 @@ func (r *ipv6Resource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 }
-
-func (r *ipv6Resource) waitForIPv6Config(model ipv6ResourceModel) bool {
-	checkStatus := func() error {
-		queryIPv6Response, err := r.client.QueryIPv6Config(model.DomainId.ValueString())
-		if err != nil {
-			return err
-		}
-
-		if *queryIPv6Response.UseIpv6 == model.EnableIpv6.ValueBool() {
-			return nil
-		}
-
-		return errors.New("deployment is in progress")
-	}
-
-	s := backoff.NewExponentialBackOff()
-	s.InitialInterval = 10 * time.Second
-	s.MaxElapsedTime = 0 // set as infinite retries.
-
-	err := backoff.Retry(checkStatus, s)
-	return err == nil
-}
@@ -0,0 +1,337 @@
+package cdnetworks
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+type advOriginConfigModel struct {
+	MasterIps types.List `tfsdk:"master_ips"`
+	BackupIps types.List `tfsdk:"backup_ips"`
+}
+
+type originFailoverConfigModel struct {
+	DomainId          types.String            `tfsdk:"domain_id"`
+	DetectUrl         types.String            `tfsdk:"detect_url"`
+	DetectPeriod      types.Int64             `tfsdk:"detect_period"`
+	AdvOriginConfigs  []*advOriginConfigModel `tfsdk:"adv_origin_config"`
+	WaitForDeployment types.Bool              `tfsdk:"wait_for_deployment"`
+	DeploymentStatus  types.String            `tfsdk:"deployment_status"`
+	Timeouts          timeouts.Value          `tfsdk:"timeouts"`
+}
+
+type originFailoverConfigResource struct {
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
+}
+
+var (
+	_ resource.Resource                = &originFailoverConfigResource{}
+	_ resource.ResourceWithConfigure   = &originFailoverConfigResource{}
+	_ resource.ResourceWithModifyPlan  = &originFailoverConfigResource{}
+	_ resource.ResourceWithImportState = &originFailoverConfigResource{}
+)
+
+func NewOriginFailoverConfigResource() resource.Resource {
+	return &originFailoverConfigResource{}
+}
+
+func (r *originFailoverConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_origin_failover_config"
+}
+
+func (r *originFailoverConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Advanced back-to-origin configuration. CDN detects the health of the master origins periodically and switches to the backup origins when all master origins are unavailable.",
+		Attributes: map[string]schema.Attribute{
+			"domain_id": &schema.StringAttribute{
+				Description: "Domain ID",
+				Required:    true,
+			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
+			"detect_url": &schema.StringAttribute{
+				Description: "The url used to detect the health of the origins. E.g: http://www.example.com/health",
+				Required:    true,
+			},
+			"detect_period": &schema.Int64Attribute{
+				Description: "The detection period, in seconds.",
+				Required:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"adv_origin_config": &schema.ListNestedBlock{
+				Description: "Master and backup origin pools.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"master_ips": &schema.ListAttribute{
+							Description: "Master origin addresses, which can be IPs or a domain name.",
+							ElementType: types.StringType,
+							Required:    true,
+							Validators: []validator.List{
+								listvalidator.SizeAtLeast(1),
+							},
+						},
+						"backup_ips": &schema.ListAttribute{
+							Description: "Backup origin addresses, which can be IPs or a domain name. Used when all master origins are unavailable.",
+							ElementType: types.StringType,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *originFailoverConfigResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
+}
+
+func (r *originFailoverConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *originFailoverConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to set origin_failover_config", err.Error())
+	}
+	resp.State.Set(ctx, model)
+}
+
+func (r *originFailoverConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model *originFailoverConfigModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	queryApiDomainResponse, err := r.client.QueryApiDomain(model.DomainId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to query origin_failover_config", err.Error())
+		return
+	}
+	configs := configuredAdvOriginConfigs(queryApiDomainResponse.OriginConfig)
+	if len(configs) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	advOriginConfigs := queryApiDomainResponse.OriginConfig.AdvOriginConfigs
+	model.DetectUrl = types.StringPointerValue(advOriginConfigs.DetectUrl)
+	if advOriginConfigs.DetectPeriod == nil {
+		model.DetectPeriod = types.Int64Null()
+	} else {
+		model.DetectPeriod = types.Int64Value(int64(*advOriginConfigs.DetectPeriod))
+	}
+	model.AdvOriginConfigs = make([]*advOriginConfigModel, 0)
+	for _, config := range configs {
+		configModel := &advOriginConfigModel{}
+		configModel.MasterIps, err = splitToStringList(ctx, config.MasterIps)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR]Fail to query origin_failover_config", err.Error())
+			return
+		}
+		configModel.BackupIps, err = splitToStringList(ctx, config.BackupIps)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR]Fail to query origin_failover_config", err.Error())
+			return
+		}
+		model.AdvOriginConfigs = append(model.AdvOriginConfigs, configModel)
+	}
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
+	resp.State.Set(ctx, model)
+}
+
+func (r *originFailoverConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan *originFailoverConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to set origin_failover_config", err.Error())
+		return
+	}
+	resp.State.Set(ctx, plan)
+}
+
+func (r *originFailoverConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model *originFailoverConfigModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	// Omitted fields are kept by vendor, clear them explicitly to remove the
+	// origin pools.
+	emptyList := types.ListValueMust(types.StringType, []attr.Value{})
+	model.DetectUrl = types.StringValue("")
+	model.DetectPeriod = types.Int64Null()
+	model.AdvOriginConfigs = []*advOriginConfigModel{{MasterIps: emptyList, BackupIps: emptyList}}
+	err := r.updateConfig(ctx, model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to delete origin_failover_config", err.Error())
+		return
+	}
+
+	queryApiDomainResponse, err := r.client.QueryApiDomain(model.DomainId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to query origin_failover_config", err.Error())
+		return
+	}
+	if len(configuredAdvOriginConfigs(queryApiDomainResponse.OriginConfig)) != 0 {
+		resp.Diagnostics.AddError("[API ERROR]Fail to delete origin_failover_config",
+			"The origin pools are still configured after being cleared.")
+	}
+}
+
+func (r *originFailoverConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan *originFailoverConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan == nil {
+		return
+	}
+
+	if len(plan.AdvOriginConfigs) == 0 {
+		resp.Diagnostics.AddError("[Validate Config]Invalid Config", "adv_origin_config is required")
+		return
+	}
+}
+
+func (r *originFailoverConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
+}
+
+func (r *originFailoverConfigResource) updateConfig(ctx context.Context, model *originFailoverConfigModel) error {
+	if model == nil {
+		return errors.New("model is nil")
+	}
+	advOriginConfigs := &cdnetworksapi.AdvOriginConfigs{
+		DetectUrl: model.DetectUrl.ValueStringPointer(),
+	}
+	if !model.DetectPeriod.IsNull() && !model.DetectPeriod.IsUnknown() {
+		detectPeriod := int(model.DetectPeriod.ValueInt64())
+		advOriginConfigs.DetectPeriod = &detectPeriod
+	}
+	for _, configModel := range model.AdvOriginConfigs {
+		config := &cdnetworksapi.AdvOriginConfig{}
+		config.MasterIps = joinStringList(ctx, configModel.MasterIps)
+		config.BackupIps = joinStringList(ctx, configModel.BackupIps)
+		advOriginConfigs.AdvOriginConfig = append(advOriginConfigs.AdvOriginConfig, config)
+	}
+	// The origin config is replaced as a whole, send back the origin ips, port and
+	// host header of the domain together so that they are not wiped.
+	queryApiDomainResponse, err := r.client.QueryApiDomain(model.DomainId.ValueString())
+	if err != nil {
+		return err
+	}
+	originConfig := &cdnetworksapi.OriginConfigInApiDomain{}
+	if current := queryApiDomainResponse.OriginConfig; current != nil {
+		originConfig.OriginIps = current.OriginIps
+		originConfig.DefaultOriginHostHeader = current.DefaultOriginHostHeader
+		originConfig.OriginPort = current.OriginPort
+	}
+	originConfig.AdvOriginConfigs = advOriginConfigs
+	updateApiDomainRequest := cdnetworksapi.UpdateApiDomainRequest{
+		OriginConfig: originConfig,
+	}
+	_, err = r.client.UpdateApiDomain(model.DomainId.ValueString(), updateApiDomainRequest)
+	if err != nil {
+		return err
+	}
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
+	return err
+}
+
+// configuredAdvOriginConfigs returns the origin pools of originConfig, the
+// ones without master origins, i.e. cleared by Delete, are skipped.
+func configuredAdvOriginConfigs(originConfig *cdnetworksapi.OriginConfigInApiDomain) []*cdnetworksapi.AdvOriginConfig {
+	if originConfig == nil || originConfig.AdvOriginConfigs == nil {
+		return nil
+	}
+	configs := make([]*cdnetworksapi.AdvOriginConfig, 0)
+	for _, config := range originConfig.AdvOriginConfigs.AdvOriginConfig {
+		if config != nil && config.MasterIps != nil && *config.MasterIps != "" {
+			configs = append(configs, config)
+		}
+	}
+	return configs
+}
+
+// joinStringList joins the elements of list with utils.Separator, returns nil
+// if the list is null or unknown.
+func joinStringList(ctx context.Context, list types.List) *string {
+	if list.IsNull() || list.IsUnknown() {
+		return nil
+	}
+	values := make([]string, 0)
+	list.ElementsAs(ctx, &values, false)
+	s := strings.Join(values, utils.Separator)
+	return &s
+}
+
+// splitToStringList splits s by utils.Separator, returns a null list if s is
+// nil or empty.
+func splitToStringList(ctx context.Context, s *string) (types.List, error) {
+	if s == nil || *s == "" {
+		return types.ListNull(types.StringType), nil
+	}
+	list, diags := types.ListValueFrom(ctx, types.StringType, strings.Split(*s, utils.Separator))
+	if diags.HasError() {
+		return list, diagsToError(diags)
+	}
+	return list, nil
+}
+
+func diagsToError(diags diag.Diagnostics) error {
+	messages := make([]string, 0)
+	for _, d := range diags.Errors() {
+		messages = append(messages, d.Summary()+": "+d.Detail())
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -15,7 +16,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
 )
 
@@ -36,10 +36,14 @@ type originRulesRewriteModel struct {
 type originRulesRewriteConfigModel struct {
 	DomainId           types.String               `tfsdk:"domain_id"`
 	OriginRulesRewrite []*originRulesRewriteModel `tfsdk:"origin_rules_rewrite"`
+	WaitForDeployment  types.Bool                 `tfsdk:"wait_for_deployment"`
+	DeploymentStatus   types.String               `tfsdk:"deployment_status"`
+	Timeouts           timeouts.Value             `tfsdk:"timeouts"`
 }
 
 type originRulesRewriteConfigResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
 }
 
 var (
@@ -56,7 +60,7 @@ func (r *originRulesRewriteConfigResource) Metadata(_ context.Context, req resou
 	resp.TypeName = req.ProviderTypeName + "_origin_rules_rewrite_config"
 }
 
-func (r *originRulesRewriteConfigResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *originRulesRewriteConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "This resource configures alternative(s) origins for specific URL paths.",
 		Attributes: map[string]schema.Attribute{
@@ -64,6 +68,8 @@ func (r *originRulesRewriteConfigResource) Schema(_ context.Context, req resourc
 				Description: "Domain ID",
 				Required:    true,
 			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
 		},
 		Blocks: map[string]schema.Block{
 			"origin_rules_rewrite": &schema.ListNestedBlock{
@@ -148,6 +154,7 @@ func (r *originRulesRewriteConfigResource) Schema(_ context.Context, req resourc
 					},
 				},
 			},
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -156,7 +163,9 @@ func (r *originRulesRewriteConfigResource) Configure(ctx context.Context, req re
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
 }
 
 func (r *originRulesRewriteConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -166,7 +175,10 @@ func (r *originRulesRewriteConfigResource) Create(ctx context.Context, req resou
 		return
 	}
 
-	err := r.updateConfig(model, nil)
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("[API Error]Fail to update origin_rules_rewrites", err.Error())
 		return
@@ -188,12 +200,16 @@ func (r *originRulesRewriteConfigResource) Read(ctx context.Context, req resourc
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	err := r.updateModel(model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API Error]Fail to query origin_rules_rewrites", err.Error())
 		return
 	}
 
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
 	resp.State.Set(ctx, model)
 }
 
@@ -210,6 +226,9 @@ func (r *originRulesRewriteConfigResource) Update(ctx context.Context, req resou
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	// CDNetworks's way to perform a delete on a single origin_rewrited_rule
 	// is to pass in only the dataId of the rule that has been marked for deletion.
 
@@ -227,7 +246,7 @@ func (r *originRulesRewriteConfigResource) Update(ctx context.Context, req resou
 	}
 
 	deletedDataIds := stateDataIds.Difference(planDataIds)
-	err := r.updateConfig(plan, deletedDataIds.ToSlice())
+	err := r.updateConfig(ctx, plan, deletedDataIds.ToSlice())
 	if err != nil {
 		resp.Diagnostics.AddError("[API Error]Fail to update origin_rules_rewrites", err.Error())
 		return
@@ -249,8 +268,11 @@ func (r *originRulesRewriteConfigResource) Delete(ctx context.Context, req resou
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	model.OriginRulesRewrite = make([]*originRulesRewriteModel, 0)
-	err := r.updateConfig(model, nil)
+	err := r.updateConfig(ctx, model, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to delete origin_rules_rewrite", err.Error())
 	}
@@ -260,7 +282,7 @@ func (r *originRulesRewriteConfigResource) ImportState(ctx context.Context, req
 	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
 }
 
-func (r *originRulesRewriteConfigResource) updateConfig(model *originRulesRewriteConfigModel, deletedDataIds []int64) error {
+func (r *originRulesRewriteConfigResource) updateConfig(ctx context.Context, model *originRulesRewriteConfigModel, deletedDataIds []int64) error {
 	rules := make([]*cdnetworksapi.OriginRulesRewrite, 0)
 	if model.OriginRulesRewrite != nil {
 		for _, rulesRewrite := range model.OriginRulesRewrite {
@@ -289,7 +311,8 @@ func (r *originRulesRewriteConfigResource) updateConfig(model *originRulesRewrit
 	if err != nil {
 		return err
 	}
-	return utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
+	return err
 }
 
 func (r *originRulesRewriteConfigResource) updateModel(model *originRulesRewriteConfigModel) error {
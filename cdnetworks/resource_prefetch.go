@@ -0,0 +1,186 @@
+package cdnetworks
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+type prefetchModel struct {
+	TaskIds           types.List     `tfsdk:"task_ids"`
+	Urls              types.List     `tfsdk:"urls"`
+	Triggers          types.Map      `tfsdk:"triggers"`
+	WaitForCompletion types.Bool     `tfsdk:"wait_for_completion"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+type prefetchResource struct {
+	client *cdnetworksapi.Client
+}
+
+var (
+	_ resource.Resource              = &prefetchResource{}
+	_ resource.ResourceWithConfigure = &prefetchResource{}
+)
+
+func NewPrefetchResource() resource.Resource {
+	return &prefetchResource{}
+}
+
+func (r *prefetchResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_prefetch"
+}
+
+func (r *prefetchResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Submit prefetch (preload) tasks to warm the CDN edges cache. New prefetch tasks are submitted whenever urls or triggers changes. The urls are split into batches of at most 400 urls per task. Destroying this resource does nothing.`,
+		Attributes: map[string]schema.Attribute{
+			"task_ids": &schema.ListAttribute{
+				Description: "The ids of the submitted prefetch tasks, one for each batch of urls.",
+				ElementType: types.StringType,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"urls": &schema.ListAttribute{
+				Description: "The urls to be prefetched. E.g: http://www.example.com/index.html",
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "Must start with http:// or https://"),
+					),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": &schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will submit new prefetch tasks.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"wait_for_completion": &schema.BoolAttribute{
+				Description: "Whether to wait until the prefetch tasks are completed. The urls failed to be prefetched are reported as warnings. Default: true",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *prefetchResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(*providerData).client
+}
+
+func (r *prefetchResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *prefetchModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	urls := toStringPointers(ctx, model.Urls)
+	taskIds := make([]string, 0)
+	for start := 0; start < len(urls); start += cdnetworksapi.PrefetchUrlsLimit {
+		end := start + cdnetworksapi.PrefetchUrlsLimit
+		if end > len(urls) {
+			end = len(urls)
+		}
+		submitPrefetchRequest := cdnetworksapi.SubmitPrefetchRequest{
+			Urls: urls[start:end],
+		}
+		submitPrefetchResponse, err := r.client.SubmitPrefetch(submitPrefetchRequest)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to submit prefetch", err.Error())
+			break
+		}
+		if submitPrefetchResponse.ItemId == nil || *submitPrefetchResponse.ItemId == "" {
+			resp.Diagnostics.AddError("[API ERROR] Fail to submit prefetch", "No task id is returned")
+			break
+		}
+		taskIds = append(taskIds, *submitPrefetchResponse.ItemId)
+	}
+	if len(taskIds) == 0 && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.AddError("[API ERROR] Fail to submit prefetch", "No prefetch task is submitted")
+		return
+	}
+
+	taskIdList, diags := types.ListValueFrom(ctx, types.StringType, taskIds)
+	resp.Diagnostics.Append(diags...)
+	model.TaskIds = taskIdList
+	// Keep the submitted tasks in state even if some batches are failed, the
+	// resource will be tainted and re-submitted on next apply.
+	if len(taskIds) > 0 {
+		resp.State.Set(ctx, model)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if model.WaitForCompletion.ValueBool() {
+		for _, taskId := range taskIds {
+			failed, err := utils.WaitForPrefetchCompleted(ctx, r.client, taskId)
+			if err != nil {
+				resp.Diagnostics.AddError("[API ERROR] Fail to wait for prefetch", err.Error())
+				return
+			}
+			if len(failed) > 0 {
+				resp.Diagnostics.AddWarning("Fail to prefetch urls", "Task "+taskId+" failed urls: "+joinTaskDetailUrls(failed))
+			}
+		}
+	}
+}
+
+func (r *prefetchResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// A prefetch task cannot be changed once submitted, keep the state as it is.
+	var model *prefetchModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.State.Set(ctx, model)
+}
+
+func (r *prefetchResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only wait_for_completion can be updated in place, which has no effect on
+	// the submitted prefetch tasks.
+	var plan *prefetchModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.State.Set(ctx, plan)
+}
+
+func (r *prefetchResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// A prefetch task cannot be revoked, just remove it from state.
+}
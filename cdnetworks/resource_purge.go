@@ -0,0 +1,239 @@
+package cdnetworks
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+type purgeModel struct {
+	TaskId            types.String   `tfsdk:"task_id"`
+	Urls              types.List     `tfsdk:"urls"`
+	Dirs              types.List     `tfsdk:"dirs"`
+	Regexes           types.List     `tfsdk:"regexes"`
+	Triggers          types.Map      `tfsdk:"triggers"`
+	WaitForCompletion types.Bool     `tfsdk:"wait_for_completion"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+type purgeResource struct {
+	client *cdnetworksapi.Client
+}
+
+var (
+	_ resource.Resource               = &purgeResource{}
+	_ resource.ResourceWithConfigure  = &purgeResource{}
+	_ resource.ResourceWithModifyPlan = &purgeResource{}
+)
+
+func NewPurgeResource() resource.Resource {
+	return &purgeResource{}
+}
+
+func (r *purgeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_purge"
+}
+
+func (r *purgeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Submit a purge (cache refresh) task to remove the cached contents from CDN edges. A new purge task is submitted whenever urls, dirs, regexes or triggers changes. Destroying this resource does nothing.`,
+		Attributes: map[string]schema.Attribute{
+			"task_id": &schema.StringAttribute{
+				Description: "The id of the submitted purge task.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"urls": &schema.ListAttribute{
+				Description: "The urls to be purged. E.g: http://www.example.com/index.html",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "Must start with http:// or https://"),
+					),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"dirs": &schema.ListAttribute{
+				Description: "The directories to be purged, must end with '/'. E.g: http://www.example.com/images/",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(regexp.MustCompile(`^https?://.*/$`), "Must start with http:// or https:// and end with /"),
+					),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"regexes": &schema.ListAttribute{
+				Description: "The urls matched by regular expression to be purged. E.g: http://www.example.com/images/.*\\.jpg",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "Must start with http:// or https://"),
+					),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": &schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will submit a new purge task.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"wait_for_completion": &schema.BoolAttribute{
+				Description: "Whether to wait until the purge task is completed. Default: false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *purgeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(*providerData).client
+}
+
+func (r *purgeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *purgeModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	submitPurgeRequest := cdnetworksapi.SubmitPurgeRequest{
+		Urls:    toStringPointers(ctx, model.Urls),
+		Dirs:    toStringPointers(ctx, model.Dirs),
+		Regexes: toStringPointers(ctx, model.Regexes),
+	}
+	submitPurgeResponse, err := r.client.SubmitPurge(submitPurgeRequest)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to submit purge", err.Error())
+		return
+	}
+	if submitPurgeResponse.ItemId == nil || *submitPurgeResponse.ItemId == "" {
+		resp.Diagnostics.AddError("[API ERROR] Fail to submit purge", "No task id is returned")
+		return
+	}
+	model.TaskId = types.StringPointerValue(submitPurgeResponse.ItemId)
+	resp.State.Set(ctx, model)
+
+	if model.WaitForCompletion.ValueBool() {
+		failed, err := utils.WaitForPurgeCompleted(ctx, r.client, model.TaskId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to wait for purge", err.Error())
+			return
+		}
+		if len(failed) > 0 {
+			resp.Diagnostics.AddError("[API ERROR] Fail to purge", "Failed urls: "+joinTaskDetailUrls(failed))
+		}
+	}
+}
+
+func (r *purgeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// A purge task cannot be changed once submitted, keep the state as it is.
+	var model *purgeModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.State.Set(ctx, model)
+}
+
+func (r *purgeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only wait_for_completion can be updated in place, which has no effect on
+	// the submitted purge task.
+	var plan *purgeModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.State.Set(ctx, plan)
+}
+
+func (r *purgeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// A purge task cannot be revoked, just remove it from state.
+}
+
+func (r *purgeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan *purgeModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan == nil {
+		return
+	}
+
+	if plan.Urls.IsUnknown() || plan.Dirs.IsUnknown() || plan.Regexes.IsUnknown() {
+		return
+	}
+
+	if len(plan.Urls.Elements()) == 0 && len(plan.Dirs.Elements()) == 0 && len(plan.Regexes.Elements()) == 0 {
+		resp.Diagnostics.AddError("[Validate Config]Invalid Config", "at least one of urls, dirs or regexes is required")
+		return
+	}
+}
+
+// toStringPointers converts a list of strings to string pointers, returns nil
+// if the list is null or unknown.
+func toStringPointers(ctx context.Context, list types.List) []*string {
+	if list.IsNull() || list.IsUnknown() {
+		return nil
+	}
+	values := make([]string, 0)
+	list.ElementsAs(ctx, &values, false)
+	pointers := make([]*string, 0, len(values))
+	for i := range values {
+		pointers = append(pointers, &values[i])
+	}
+	return pointers
+}
+
+func joinTaskDetailUrls(details []*cdnetworksapi.ContentTaskDetail) string {
+	urls := make([]string, 0, len(details))
+	for _, detail := range details {
+		if detail.Url == nil {
+			continue
+		}
+		urls = append(urls, *detail.Url)
+	}
+	return strings.Join(urls, ", ")
+}
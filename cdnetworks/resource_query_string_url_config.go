@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -37,10 +38,15 @@ type queryStringSettingModel struct {
 type queryStringUrlConfigModel struct {
 	DomainId            types.String               `tfsdk:"domain_id"`
 	QueryStringSettings []*queryStringSettingModel `tfsdk:"query_string_setting"`
+	PurgeOnChange       *purgeOnChangeModel        `tfsdk:"purge_on_change"`
+	WaitForDeployment   types.Bool                 `tfsdk:"wait_for_deployment"`
+	DeploymentStatus    types.String               `tfsdk:"deployment_status"`
+	Timeouts            timeouts.Value             `tfsdk:"timeouts"`
 }
 
 type queryStringUrlConfigResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
 }
 
 var (
@@ -58,7 +64,7 @@ func (r *queryStringUrlConfigResource) Metadata(_ context.Context, req resource.
 	resp.TypeName = req.ProviderTypeName + "_query_string_url_config"
 }
 
-func (r *queryStringUrlConfigResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *queryStringUrlConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: `With the query string URL, you can set whether to cache multiple copies or cache the URL after removing the question mark (to increase the hit rate), and you can set whether to use the original request to return to the source, etc.`,
 		Attributes: map[string]schema.Attribute{
@@ -66,8 +72,11 @@ func (r *queryStringUrlConfigResource) Schema(_ context.Context, req resource.Sc
 				Description: "Domain id",
 				Required:    true,
 			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
 		},
 		Blocks: map[string]schema.Block{
+			"purge_on_change": purgeOnChangeBlock(),
 			"query_string_setting": &schema.ListNestedBlock{
 				Description: `Query String Settings Configuration`,
 				NestedObject: schema.NestedBlockObject{
@@ -152,6 +161,7 @@ When ignore-query-string is false, this default setting is empty (input is inval
 					},
 				},
 			},
+			"timeouts": timeoutsBlock(ctx),
 		},
 	}
 }
@@ -160,7 +170,9 @@ func (r *queryStringUrlConfigResource) Configure(ctx context.Context, req resour
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
 }
 
 func (r *queryStringUrlConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -170,7 +182,10 @@ func (r *queryStringUrlConfigResource) Create(ctx context.Context, req resource.
 		return
 	}
 
-	err := r.updateConfig(model)
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to set query_string_url_config", err.Error())
 	}
@@ -184,12 +199,16 @@ func (r *queryStringUrlConfigResource) Read(ctx context.Context, req resource.Re
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	err := r.updateModel(model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to read_query_string_url_config", err.Error())
 		return
 	}
 
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
 	resp.State.Set(ctx, model)
 }
 
@@ -199,7 +218,11 @@ func (r *queryStringUrlConfigResource) Update(ctx context.Context, req resource.
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.updateConfig(plan)
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to set query_string_url_config", err.Error())
 		return
@@ -213,8 +236,14 @@ func (r *queryStringUrlConfigResource) Delete(ctx context.Context, req resource.
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	model.QueryStringSettings = make([]*queryStringSettingModel, 0)
-	err := r.updateConfig(model)
+	// purge_on_change applies to the configuration changes only, not to destroy.
+	model.PurgeOnChange = nil
+	err := r.updateConfig(ctx, model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to delete query_string_url_config", err.Error())
 	}
@@ -240,6 +269,10 @@ func (r *queryStringUrlConfigResource) ModifyPlan(ctx context.Context, req resou
 			}
 		}
 	}
+	if err := plan.PurgeOnChange.check(); err != nil {
+		resp.Diagnostics.AddError("[Validate Config]Invalid Config", err.Error())
+		return
+	}
 	resp.Plan.Set(ctx, plan)
 }
 
@@ -247,7 +280,7 @@ func (r *queryStringUrlConfigResource) ImportState(ctx context.Context, req reso
 	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
 }
 
-func (r *queryStringUrlConfigResource) updateConfig(model *queryStringUrlConfigModel) error {
+func (r *queryStringUrlConfigResource) updateConfig(ctx context.Context, model *queryStringUrlConfigModel) error {
 	settings := make([]*cdnetworksapi.QueryStringSetting, 0)
 	if model.QueryStringSettings != nil {
 		for _, settingModel := range model.QueryStringSettings {
@@ -276,18 +309,13 @@ func (r *queryStringUrlConfigResource) updateConfig(model *queryStringUrlConfigM
 	if err != nil {
 		return err
 	}
-	return utils.WaitForDomainDeployed(r.client, model.DomainId.ValueString())
+	wait := shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment)
+	model.DeploymentStatus, err = waitAndPurge(ctx, r.client, model.DomainId.ValueString(), wait, model.PurgeOnChange)
+	return err
 }
 
 func (r *queryStringUrlConfigResource) updateModel(model *queryStringUrlConfigModel) error {
-	settingIndexMap := make(map[string][]int)
-	for i, setting := range model.QueryStringSettings {
-		list, ok := settingIndexMap[setting.String()]
-		if !ok {
-			list = make([]int, 0)
-		}
-		settingIndexMap[setting.String()] = append(list, i)
-	}
+	priorSettings := model.QueryStringSettings
 
 	queryQueryStringConfigResponse, err := r.client.QueryQueryStringConfig(model.DomainId.ValueString())
 	if err != nil {
@@ -320,26 +348,7 @@ func (r *queryStringUrlConfigResource) updateModel(model *queryStringUrlConfigMo
 	}
 
 	// Sort settings to align with the configuaration file
-	queryStringSettings := make([]*queryStringSettingModel, len(model.QueryStringSettings))
-	unmatchedSettings := make([]*queryStringSettingModel, 0)
-	for i := 0; i < len(model.QueryStringSettings); i++ {
-		setting := model.QueryStringSettings[i]
-		list, ok := settingIndexMap[setting.String()]
-		if ok && len(list) > 0 {
-			queryStringSettings[list[0]] = setting
-			settingIndexMap[setting.String()] = list[1:]
-		} else {
-			unmatchedSettings = append(unmatchedSettings, setting)
-		}
-	}
-	for i, j := 0, 0; i < len(queryStringSettings) && j < len(unmatchedSettings); i++ {
-		if queryStringSettings[i] != nil {
-			continue
-		}
-		queryStringSettings[i] = unmatchedSettings[j]
-		j++
-	}
-	model.QueryStringSettings = queryStringSettings
+	model.QueryStringSettings = alignToPrior(priorSettings, model.QueryStringSettings)
 
 	return nil
 }
@@ -0,0 +1,185 @@
+package cdnetworks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+type rangeAndFollowConfigModel struct {
+	DomainId          types.String   `tfsdk:"domain_id"`
+	UseRange          types.Bool     `tfsdk:"use_range"`
+	Follow301         types.Bool     `tfsdk:"follow_301"`
+	Follow302         types.Bool     `tfsdk:"follow_302"`
+	WaitForDeployment types.Bool     `tfsdk:"wait_for_deployment"`
+	DeploymentStatus  types.String   `tfsdk:"deployment_status"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+type rangeAndFollowConfigResource struct {
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
+}
+
+var (
+	_ resource.Resource                = &rangeAndFollowConfigResource{}
+	_ resource.ResourceWithConfigure   = &rangeAndFollowConfigResource{}
+	_ resource.ResourceWithImportState = &rangeAndFollowConfigResource{}
+)
+
+func NewRangeAndFollowConfigResource() resource.Resource {
+	return &rangeAndFollowConfigResource{}
+}
+
+func (r *rangeAndFollowConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_range_and_follow_config"
+}
+
+func (r *rangeAndFollowConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Origin fetch behaviours, including range request to origin and whether to follow 301/302 redirects returned by origin.",
+		Attributes: map[string]schema.Attribute{
+			"domain_id": &schema.StringAttribute{
+				Description: "Domain ID",
+				Required:    true,
+			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
+			"use_range": &schema.BoolAttribute{
+				Description: "Whether to fetch content from origin by range requests. Default: false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"follow_301": &schema.BoolAttribute{
+				Description: "Whether CDN follows the 301 redirect returned by origin instead of returning it to client. Default: false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"follow_302": &schema.BoolAttribute{
+				Description: "Whether CDN follows the 302 redirect returned by origin instead of returning it to client. Default: false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *rangeAndFollowConfigResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
+}
+
+func (r *rangeAndFollowConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *rangeAndFollowConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to set range_and_follow_config", err.Error())
+	}
+	resp.State.Set(ctx, model)
+}
+
+func (r *rangeAndFollowConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model *rangeAndFollowConfigModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	queryDomainResponse, err := r.client.QueryDomain(model.DomainId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to query range_and_follow_config", err.Error())
+		return
+	}
+	// The vendor omits these fields when they are not enabled.
+	model.UseRange = types.BoolValue(queryDomainResponse.UseRange != nil && *queryDomainResponse.UseRange)
+	model.Follow301 = types.BoolValue(queryDomainResponse.Follow301 != nil && *queryDomainResponse.Follow301)
+	model.Follow302 = types.BoolValue(queryDomainResponse.Follow302 != nil && *queryDomainResponse.Follow302)
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
+	resp.State.Set(ctx, model)
+}
+
+func (r *rangeAndFollowConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan *rangeAndFollowConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to set range_and_follow_config", err.Error())
+		return
+	}
+	resp.State.Set(ctx, plan)
+}
+
+func (r *rangeAndFollowConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model *rangeAndFollowConfigModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	model.UseRange = types.BoolValue(false)
+	model.Follow301 = types.BoolValue(false)
+	model.Follow302 = types.BoolValue(false)
+	err := r.updateConfig(ctx, model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to delete range_and_follow_config", err.Error())
+	}
+}
+
+func (r *rangeAndFollowConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
+}
+
+func (r *rangeAndFollowConfigResource) updateConfig(ctx context.Context, model *rangeAndFollowConfigModel) error {
+	if model == nil {
+		return errors.New("model is nil")
+	}
+	updateRangeAndFollowConfigRequest := cdnetworksapi.UpdateRangeAndFollowConfigRequest{
+		UseRange:  model.UseRange.ValueBoolPointer(),
+		Follow301: model.Follow301.ValueBoolPointer(),
+		Follow302: model.Follow302.ValueBoolPointer(),
+	}
+	_, err := r.client.UpdateRangeAndFollowConfig(model.DomainId.ValueString(), updateRangeAndFollowConfigRequest)
+	if err != nil {
+		return err
+	}
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
+	return err
+}
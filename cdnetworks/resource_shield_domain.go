@@ -0,0 +1,196 @@
+package cdnetworks
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	. "github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/model"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+// shieldDomainResource reuses domainResource to manage the domain, and manages
+// the origin shield configuration on top of it.
+type shieldDomainResource struct {
+	domainResource
+}
+
+var (
+	_ resource.Resource                = &shieldDomainResource{}
+	_ resource.ResourceWithConfigure   = &shieldDomainResource{}
+	_ resource.ResourceWithImportState = &shieldDomainResource{}
+	_ resource.ResourceWithModifyPlan  = &shieldDomainResource{}
+)
+
+func NewShieldDomainResource() resource.Resource {
+	return &shieldDomainResource{domainResource{product: originShieldProduct}}
+}
+
+func (r *shieldDomainResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = ShieldDomainSchema
+	resp.Schema.Blocks = map[string]schema.Block{
+		"timeouts": timeoutsBlock(ctx),
+	}
+}
+
+func (r *shieldDomainResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model ShieldDomainResourceModel
+	resp.Diagnostics.Append(model.GetFrom(ctx, req.Plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	// Let vendor select the shield nodes if not specified.
+	if model.ShieldNodes.IsUnknown() {
+		model.ShieldNodes = types.ListNull(types.StringType)
+	}
+
+	r.createDomain(ctx, &model.DomainResourceModel, func() diag.Diagnostics {
+		return model.SetTo(ctx, &resp.State)
+	}, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.updateShieldConfig(ctx, &model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to set origin shield", err.Error())
+		return
+	}
+	err = r.updateShieldModel(ctx, &model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to query origin shield", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(model.SetTo(ctx, &resp.State)...)
+}
+
+func (r *shieldDomainResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model ShieldDomainResourceModel
+	resp.Diagnostics.Append(model.GetFrom(ctx, req.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	r.readDomain(ctx, &model.DomainResourceModel, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() {
+		err := r.updateShieldModel(ctx, &model)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to query origin shield", err.Error())
+		}
+	}
+
+	resp.Diagnostics.Append(model.SetTo(ctx, &resp.State)...)
+}
+
+func (r *shieldDomainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan ShieldDomainResourceModel
+	resp.Diagnostics.Append(plan.GetFrom(ctx, req.Plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(state.GetFrom(ctx, req.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	r.updateDomain(ctx, &plan.DomainResourceModel, &state.DomainResourceModel, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.ShieldRegion.Equal(state.ShieldRegion) || !plan.ShieldNodes.Equal(state.ShieldNodes) {
+		if plan.ShieldNodes.IsUnknown() {
+			plan.ShieldNodes = types.ListNull(types.StringType)
+		}
+		err := r.updateShieldConfig(ctx, &plan)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to set origin shield", err.Error())
+			return
+		}
+	}
+	err := r.updateShieldModel(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to query origin shield", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(plan.SetTo(ctx, &resp.State)...)
+}
+
+func (r *shieldDomainResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model ShieldDomainResourceModel
+	resp.Diagnostics.Append(model.GetFrom(ctx, req.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	// Origin shield configuration is removed together with the domain.
+	r.deleteDomain(ctx, &model.DomainResourceModel, &resp.Diagnostics)
+}
+
+func (r *shieldDomainResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan ShieldDomainResourceModel
+	resp.Diagnostics.Append(plan.GetFrom(ctx, req.Plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.modifyDomainPlan(&plan.DomainResourceModel, req.State.Raw.IsNull(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(plan.SetTo(ctx, &resp.Plan)...)
+}
+
+func (r *shieldDomainResource) updateShieldConfig(ctx context.Context, model *ShieldDomainResourceModel) error {
+	updateOriginShieldConfigRequest := cdnetworksapi.UpdateOriginShieldConfigRequest{
+		OriginShieldConfig: &cdnetworksapi.OriginShieldConfig{
+			ShieldRegion: model.ShieldRegion.ValueStringPointer(),
+			ShieldNodes:  joinStringList(ctx, model.ShieldNodes),
+		},
+	}
+	_, err := r.client.UpdateOriginShieldConfig(model.DomainId.ValueString(), updateOriginShieldConfigRequest)
+	if err != nil {
+		return err
+	}
+	return utils.WaitForDomainDeployed(ctx, r.client, model.DomainId.ValueString())
+}
+
+func (r *shieldDomainResource) updateShieldModel(ctx context.Context, model *ShieldDomainResourceModel) error {
+	queryOriginShieldConfigResponse, err := r.client.QueryOriginShieldConfig(model.DomainId.ValueString())
+	if err != nil {
+		return err
+	}
+	config := queryOriginShieldConfigResponse.OriginShieldConfig
+	if config == nil {
+		model.ShieldNodes = types.ListNull(types.StringType)
+		return nil
+	}
+	// shield_region is required, keep the configured value if vendor responds
+	// nothing, e.g. the configuration is not deployed yet.
+	if config.ShieldRegion != nil && *config.ShieldRegion != "" {
+		model.ShieldRegion = types.StringValue(*config.ShieldRegion)
+	}
+	model.ShieldNodes, err = splitToStringList(ctx, config.ShieldNodes)
+	return err
+}
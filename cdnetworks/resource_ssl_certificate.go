@@ -2,31 +2,108 @@ package cdnetworks
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworks/utils"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
 )
 
 type sslCertificateResourceModel struct {
-	Id             types.String `tfsdk:"ssl_certificate_id"`
-	Name           types.String `tfsdk:"name"`
-	Comment        types.String `tfsdk:"comment"`
-	SslCertificate types.String `tfsdk:"ssl_certificate"`
-	SslKey         types.String `tfsdk:"ssl_key"`
+	Id                      types.String   `tfsdk:"ssl_certificate_id"`
+	Name                    types.String   `tfsdk:"name"`
+	Comment                 types.String   `tfsdk:"comment"`
+	SslCertificate          types.String   `tfsdk:"ssl_certificate"`
+	SslKey                  types.String   `tfsdk:"ssl_key"`
+	CsrId                   types.String   `tfsdk:"csr_id"`
+	CertificateChain        types.String   `tfsdk:"certificate_chain"`
+	ExpiryWarningDays       types.Int64    `tfsdk:"expiry_warning_days"`
+	FailOnExpired           types.Bool     `tfsdk:"fail_on_expired"`
+	UpdateMode              types.String   `tfsdk:"update_mode"`
+	ForceDetach             types.Bool     `tfsdk:"force_detach"`
+	CrtMd5                  types.String   `tfsdk:"crt_md5"`
+	KeyMd5                  types.String   `tfsdk:"key_md5"`
+	CaMd5                   types.String   `tfsdk:"ca_md5"`
+	NotBefore               types.String   `tfsdk:"not_before"`
+	NotAfter                types.String   `tfsdk:"not_after"`
+	Serial                  types.String   `tfsdk:"serial"`
+	Issuer                  types.String   `tfsdk:"issuer"`
+	CommonName              types.String   `tfsdk:"common_name"`
+	SubjectAlternativeNames types.List     `tfsdk:"subject_alternative_names"`
+	RelatedDomains          types.List     `tfsdk:"related_domains"`
+	Timeouts                timeouts.Value `tfsdk:"timeouts"`
 }
 
+// privateState is implemented by the private state of the requests and
+// responses.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// certificateOutdated returns true if the certificate at vendor is to be
+// uploaded again, either the certificate, chain, key or CSR of m differs from
+// state, or the fingerprints computed from m differ from the ones refreshed
+// from vendor into state, i.e. the certificate is replaced out of Terraform.
+func (m *sslCertificateResourceModel) certificateOutdated(state *sslCertificateResourceModel) bool {
+	if !m.SslCertificate.Equal(state.SslCertificate) || !m.CertificateChain.Equal(state.CertificateChain) ||
+		!m.SslKey.Equal(state.SslKey) || !m.CsrId.Equal(state.CsrId) {
+		return true
+	}
+
+	bundle, diags := m.certificateBundle()
+	if diags.HasError() {
+		return false
+	}
+	computed := bundle.fingerprints(m.SslKey.ValueString())
+	// The private key of the CSR is kept by vendor, it is never uploaded.
+	return fingerprintChanged(state.CrtMd5, computed.crtMd5) ||
+		fingerprintChanged(state.CaMd5, computed.caMd5) ||
+		(m.CsrId.IsNull() && fingerprintChanged(state.KeyMd5, computed.keyMd5))
+}
+
+// fingerprintChanged returns true if the fingerprint refreshed from vendor
+// differs from the computed one. It is not changed if vendor reports none.
+func fingerprintChanged(refreshed types.String, computed string) bool {
+	return !refreshed.IsNull() && !refreshed.IsUnknown() && refreshed.ValueString() != computed
+}
+
+// certificateBundle parses and validates ssl_certificate, certificate_chain
+// and ssl_key, which must be known.
+func (m *sslCertificateResourceModel) certificateBundle() (*certificateBundle, diag.Diagnostics) {
+	return parseCertificateBundle(
+		m.SslCertificate.ValueString(), m.CertificateChain.ValueString(), m.SslKey.ValueString(),
+		path.Root("ssl_certificate"), path.Root("certificate_chain"), path.Root("ssl_key"),
+	)
+}
+
+const (
+	certificateUpdateModeInPlace = "in_place"
+	certificateUpdateModeRotate  = "rotate"
+)
+
 type sslCertificateResource struct {
-	client *cdnetworksapi.Client
+	client            *cdnetworksapi.Client
+	expiryWarningDays int64
 }
 
 var (
 	_ resource.Resource                = &sslCertificateResource{}
 	_ resource.ResourceWithConfigure   = &sslCertificateResource{}
 	_ resource.ResourceWithImportState = &sslCertificateResource{}
+	_ resource.ResourceWithModifyPlan  = &sslCertificateResource{}
 )
 
 func NewSslCertificateResource() resource.Resource {
@@ -37,7 +114,7 @@ func (r *sslCertificateResource) Metadata(_ context.Context, req resource.Metada
 	resp.TypeName = req.ProviderTypeName + "_ssl_certificate"
 }
 
-func (r *sslCertificateResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *sslCertificateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "The resource provides a SSL certificate for domain",
 		Attributes: map[string]schema.Attribute{
@@ -54,15 +131,112 @@ func (r *sslCertificateResource) Schema(_ context.Context, req resource.SchemaRe
 				Optional:    true,
 			},
 			"ssl_certificate": &schema.StringAttribute{
-				Description: "Certificate, PEM certificate, including CRT file and CA file.",
-				Required:    true,
+				Description: "Certificate, PEM certificate, including CRT file and CA file. " +
+					"The certificates are validated at plan time, and uploaded in the order of the leaf certificate followed by its issuers.",
+				Required: true,
+			},
+			"certificate_chain": &schema.StringAttribute{
+				Description: "PEM encoded CA certificates of ssl_certificate, which are uploaded together with ssl_certificate. " +
+					"Used when the CA certificates are not included in ssl_certificate.",
+				Optional: true,
 			},
 			"ssl_key": &schema.StringAttribute{
-				Description: "Private key of the certificate, PEM certificate.",
-				Required:    true,
+				Description: "Private key of the certificate, PEM certificate. Either ssl_key or csr_id must be set.",
+				Optional:    true,
 				Sensitive:   true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("csr_id")),
+				},
+			},
+			"csr_id": &schema.StringAttribute{
+				Description: "ID of the st-cdnetworks_certificate_signing_request which ssl_certificate is signed from. " +
+					"The private key is kept by vendor, so only the signed certificate is uploaded. Either ssl_key or csr_id must be set.",
+				Optional: true,
+			},
+			"expiry_warning_days": &schema.Int64Attribute{
+				Description: "Warn at plan time if the certificate expires within the number of days. Defaults to expiry_warning_days of the provider.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"fail_on_expired": &schema.BoolAttribute{
+				Description: "Fail the plan if any certificate of the chain is expired, otherwise only warn. Default: true",
+				Optional:    true,
+			},
+			"update_mode": &schema.StringAttribute{
+				Description: `How the certificate or key is updated, the optional value is
+                                    in_place: update the certificate in place, the certificate ID is unchanged.
+                                    rotate: upload a new certificate, point the domains using the old certificate to the new one, and delete the old certificate after the domains are deployed. The certificate ID is changed.
+                                    Default: in_place`,
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(certificateUpdateModeInPlace),
+				Validators: []validator.String{
+					stringvalidator.OneOf(certificateUpdateModeInPlace, certificateUpdateModeRotate),
+				},
+			},
+			"force_detach": &schema.BoolAttribute{
+				Description: "Disable HTTPS of the domains using the certificate before deleting it. Otherwise deleting the certificate used by any domain fails. Default: false",
+				Optional:    true,
+			},
+			"crt_md5": &schema.StringAttribute{
+				Description: "MD5 fingerprint of the certificate reported by vendor. The certificate is planned to be uploaded again if it differs from the one computed from ssl_certificate, i.e. it changes out of Terraform.",
+				Computed:    true,
+			},
+			"key_md5": &schema.StringAttribute{
+				Description: "MD5 fingerprint of the private key reported by vendor. The certificate is planned to be uploaded again if it differs from the one computed from ssl_key, i.e. it changes out of Terraform.",
+				Computed:    true,
+			},
+			"ca_md5": &schema.StringAttribute{
+				Description: "MD5 fingerprint of the CA certificates reported by vendor, null if there is none. The certificate is planned to be uploaded again if it differs from the one computed from the CA certificates of ssl_certificate and certificate_chain.",
+				Computed:    true,
+			},
+			"not_before": &schema.StringAttribute{
+				Description: "The time from which the certificate is valid, in RFC3339 format.",
+				Computed:    true,
+			},
+			"not_after": &schema.StringAttribute{
+				Description: "The time after which the certificate is expired, in RFC3339 format.",
+				Computed:    true,
+			},
+			"serial": &schema.StringAttribute{
+				Description: "Serial number of the certificate.",
+				Computed:    true,
+			},
+			"issuer": &schema.StringAttribute{
+				Description: "Issuer of the certificate.",
+				Computed:    true,
+			},
+			"common_name": &schema.StringAttribute{
+				Description: "Common name of the certificate subject.",
+				Computed:    true,
+			},
+			"subject_alternative_names": &schema.ListAttribute{
+				Description: "DNS names covered by the certificate.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"related_domains": &schema.ListNestedAttribute{
+				Description: "Domains using the certificate.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"domain_id": &schema.StringAttribute{
+							Description: "Domain ID",
+							Computed:    true,
+						},
+						"domain_name": &schema.StringAttribute{
+							Description: "Domain name",
+							Computed:    true,
+						},
+					},
+				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
 	}
 }
 
@@ -70,7 +244,9 @@ func (r *sslCertificateResource) Configure(_ context.Context, req resource.Confi
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.expiryWarningDays = data.expiryWarningDays
 }
 
 func (r *sslCertificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -81,23 +257,27 @@ func (r *sslCertificateResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	addCertificateRequest := cdnetworksapi.AddCertificateV2Request{
-		Name:        model.Name.ValueStringPointer(),
-		Certificate: model.SslCertificate.ValueStringPointer(),
-		PrivateKey:  model.SslKey.ValueStringPointer(),
-		Comment:     model.Comment.ValueStringPointer(),
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	bundle, diags := model.certificateBundle()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	addCertificateResponse, err := r.client.AddCertificateV2(addCertificateRequest)
+	certificateId, err := r.uploadCertificate("", model.Name.ValueString(), &model, bundle)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Failed to Add Certificate", err.Error())
 		return
 	}
-	if *addCertificateResponse.Code != "0" {
-		resp.Diagnostics.AddError("[API ERROR] Fail to Add Certificate", *addCertificateResponse.Message)
-		return
+	model.Id = types.StringValue(certificateId)
+
+	// Save the certificate even if the metadata fails to be queried.
+	_, _, err = r.readCertificate(ctx, &model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Query Certificate", err.Error())
 	}
-	model.Id = types.StringValue(*addCertificateResponse.CertificateId)
 	resp.State.Set(ctx, &model)
 }
 
@@ -108,21 +288,20 @@ func (r *sslCertificateResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	queryCertificateInfoResponse, err := r.client.QueryCertificateInfo(state.Id.ValueString())
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	info, _, err := r.readCertificate(ctx, state)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Query Certificate", err.Error())
 		return
 	}
-	if *queryCertificateInfoResponse.Code == 19638021 {
+	if info == nil {
 		resp.State.RemoveResource(ctx)
 		return
-	} else if *queryCertificateInfoResponse.Code != 0 {
-		resp.Diagnostics.AddError("[API ERROR] Fail to Query Certificate", *queryCertificateInfoResponse.Message)
-		return
 	}
-
-	state.Name = types.StringPointerValue(queryCertificateInfoResponse.QueryCertificateInfoResponseData.Name)
-	state.Comment = types.StringPointerValue(queryCertificateInfoResponse.QueryCertificateInfoResponseData.Comment)
+	state.Name = types.StringPointerValue(info.Name)
+	state.Comment = types.StringPointerValue(info.Comment)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
@@ -138,26 +317,43 @@ func (r *sslCertificateResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	updateCertificateRequest := cdnetworksapi.UpdateCertificateV2Request{
-		Name:        plan.Name.ValueStringPointer(),
-		Certificate: plan.SslCertificate.ValueStringPointer(),
-		PrivateKey:  plan.SslKey.ValueStringPointer(),
-		Comment:     plan.Comment.ValueStringPointer(),
-	}
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
 
-	updateCertificateResponse, err := r.client.UpdateCertificateV2(state.Id.ValueString(), updateCertificateRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("[API ERROR] Failed to Update Certificate", err.Error())
+	bundle, diags := plan.certificateBundle()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	if *updateCertificateResponse.Code != "0" {
-		resp.Diagnostics.AddError("[API ERROR] Failed to Update Certificate", *updateCertificateResponse.Message)
-		return
+
+	outdated := plan.certificateOutdated(&state)
+	if plan.UpdateMode.ValueString() == certificateUpdateModeRotate && outdated {
+		if !r.rotateCertificate(ctx, &plan, &state, bundle, &resp.Diagnostics) {
+			resp.State.Set(ctx, state)
+			return
+		}
+	} else {
+		_, err := r.uploadCertificate(state.Id.ValueString(), plan.Name.ValueString(), &plan, bundle)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Failed to Update Certificate", err.Error())
+			return
+		}
 	}
 	state.Name = plan.Name
 	state.SslCertificate = plan.SslCertificate
 	state.SslKey = plan.SslKey
+	state.CsrId = plan.CsrId
+	state.CertificateChain = plan.CertificateChain
+	state.ExpiryWarningDays = plan.ExpiryWarningDays
+	state.FailOnExpired = plan.FailOnExpired
+	state.UpdateMode = plan.UpdateMode
+	state.ForceDetach = plan.ForceDetach
+	state.Timeouts = plan.Timeouts
 
+	_, _, err := r.readCertificate(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Query Certificate", err.Error())
+	}
 	resp.State.Set(ctx, state)
 }
 
@@ -169,6 +365,27 @@ func (r *sslCertificateResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	queryCertificateResponse, err := r.client.QueryCertificate(model.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Query Certificate", err.Error())
+		return
+	}
+	if domains := queryCertificateResponse.RelatedDomains; len(domains) > 0 {
+		if !model.ForceDetach.ValueBool() {
+			resp.Diagnostics.AddError("[API ERROR] Failed to Del Certificate",
+				fmt.Sprintf("The certificate is used by the domains: %s. Detach the certificate from the domains first, or set force_detach to true.", formatCertificateDomains(domains)))
+			return
+		}
+		err = r.detachDomains(ctx, domains)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Detach Certificate", err.Error())
+			return
+		}
+	}
+
 	deleteCertificateResponse, err := r.client.DeleteCertificateV2(model.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Failed to Del Certificate", err.Error())
@@ -180,6 +397,417 @@ func (r *sslCertificateResource) Delete(ctx context.Context, req resource.Delete
 	}
 }
 
+// uploadCertificate adds the certificate named name if certificateId is empty,
+// otherwise updates the certificate certificateId, and returns the certificate
+// ID. If csr_id of model is set, only the certificates are uploaded to
+// complete the CSR, whose private key is kept by vendor.
+func (r *sslCertificateResource) uploadCertificate(certificateId, name string, model *sslCertificateResourceModel, bundle *certificateBundle) (string, error) {
+	if !model.CsrId.IsNull() {
+		certificate, chain := bundle.leafPem(), bundle.chainPem()
+		request := cdnetworksapi.AddCertificateRequest{
+			CsrId:          model.CsrId.ValueStringPointer(),
+			Name:           &name,
+			Comment:        model.Comment.ValueStringPointer(),
+			SslCertificate: &certificate,
+		}
+		if chain != "" {
+			request.SslCertificateChain = &chain
+		}
+		if certificateId == "" {
+			addCertificateResponse, err := r.client.AddCertificate(request)
+			if err != nil {
+				return "", err
+			}
+			return *addCertificateResponse.CertificateId, nil
+		}
+		_, err := r.client.UpdateCertificate(certificateId, request)
+		return certificateId, err
+	}
+
+	certificate := bundle.pem()
+	if certificateId == "" {
+		addCertificateResponse, err := r.client.AddCertificateV2(cdnetworksapi.AddCertificateV2Request{
+			Name:        &name,
+			Certificate: &certificate,
+			PrivateKey:  model.SslKey.ValueStringPointer(),
+			Comment:     model.Comment.ValueStringPointer(),
+		})
+		if err != nil {
+			return "", err
+		}
+		if *addCertificateResponse.Code != "0" {
+			return "", errors.New(*addCertificateResponse.Message)
+		}
+		return *addCertificateResponse.CertificateId, nil
+	}
+
+	updateCertificateResponse, err := r.client.UpdateCertificateV2(certificateId, cdnetworksapi.UpdateCertificateV2Request{
+		Name:        &name,
+		Certificate: &certificate,
+		PrivateKey:  model.SslKey.ValueStringPointer(),
+		Comment:     model.Comment.ValueStringPointer(),
+	})
+	if err != nil {
+		return "", err
+	}
+	if *updateCertificateResponse.Code != "0" {
+		return "", errors.New(*updateCertificateResponse.Message)
+	}
+	return certificateId, nil
+}
+
+// readCertificate refreshes the metadata and fingerprints of the certificate,
+// returns the
+// responses for further checking, or nil if the certificate is not found. The
+// metadata is null if it fails to be queried.
+func (r *sslCertificateResource) readCertificate(ctx context.Context, model *sslCertificateResourceModel) (*cdnetworksapi.QueryCertificateInfoResponseData, *cdnetworksapi.QueryCertificateResponse, error) {
+	model.NotBefore = types.StringNull()
+	model.NotAfter = types.StringNull()
+	model.Serial = types.StringNull()
+	model.Issuer = types.StringNull()
+	model.CommonName = types.StringNull()
+	model.SubjectAlternativeNames = types.ListNull(types.StringType)
+	model.RelatedDomains = types.ListNull(types.ObjectType{AttrTypes: certificateDomainAttributeTypes})
+	model.CrtMd5 = types.StringNull()
+	model.KeyMd5 = types.StringNull()
+	model.CaMd5 = types.StringNull()
+
+	queryCertificateInfoResponse, err := r.client.QueryCertificateInfo(model.Id.ValueString())
+	if err != nil {
+		return nil, nil, err
+	}
+	if *queryCertificateInfoResponse.Code == certificateNotFoundCode {
+		return nil, nil, nil
+	} else if *queryCertificateInfoResponse.Code != 0 {
+		return nil, nil, errors.New(*queryCertificateInfoResponse.Message)
+	}
+	queryCertificateResponse, err := r.client.QueryCertificate(model.Id.ValueString())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := queryCertificateInfoResponse.QueryCertificateInfoResponseData
+	model.Serial = types.StringPointerValue(info.Serial)
+	if model.Serial.IsNull() {
+		model.Serial = types.StringPointerValue(queryCertificateResponse.CertificateSerial)
+	}
+	model.NotBefore = certificateTimeValue(info.NotBefore)
+	if model.NotBefore.IsNull() {
+		model.NotBefore = certificateTimeValue(queryCertificateResponse.CertificateValidityFrom)
+	}
+	model.NotAfter = certificateTimeValue(info.NotAfter)
+	if model.NotAfter.IsNull() {
+		model.NotAfter = certificateTimeValue(queryCertificateResponse.CertificateValidityTo)
+	}
+	model.Issuer = types.StringPointerValue(queryCertificateResponse.CertificateIssuer)
+	model.CrtMd5 = md5Value(queryCertificateResponse.CrtMd5)
+	model.KeyMd5 = md5Value(queryCertificateResponse.KeyMd5)
+	model.CaMd5 = md5Value(queryCertificateResponse.CaMd5)
+	model.CommonName = types.StringPointerValue(info.CommonName)
+
+	var diags diag.Diagnostics
+	sans := info.SubjectAlternativeNames
+	if len(sans) == 0 {
+		for _, dnsName := range queryCertificateResponse.DnsNames {
+			if dnsName != nil {
+				sans = append(sans, *dnsName)
+			}
+		}
+	}
+	model.SubjectAlternativeNames, diags = stringsValue(ctx, sans)
+	if diags.HasError() {
+		return nil, nil, errors.New("fail to convert subject_alternative_names")
+	}
+	model.RelatedDomains, diags = certificateDomainsValue(queryCertificateResponse.RelatedDomains)
+	if diags.HasError() {
+		return nil, nil, errors.New("fail to convert related_domains")
+	}
+	return info, &queryCertificateResponse, nil
+}
+
+func (r *sslCertificateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan *sslCertificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if plan == nil {
+		r.warnDestroyingUsedCertificate(ctx, req, resp)
+		return
+	}
+
+	if !plan.SslCertificate.IsUnknown() && !plan.CertificateChain.IsUnknown() && !plan.SslKey.IsUnknown() {
+		bundle, diags := plan.certificateBundle()
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		warningDays := r.expiryWarningDays
+		if !plan.ExpiryWarningDays.IsNull() && !plan.ExpiryWarningDays.IsUnknown() {
+			warningDays = plan.ExpiryWarningDays.ValueInt64()
+		}
+		failOnExpired := plan.FailOnExpired.IsNull() || plan.FailOnExpired.IsUnknown() || plan.FailOnExpired.ValueBool()
+		for _, c := range bundle.chain {
+			checkCertificateExpiry(c.path, c.cert.Subject.String(), c.cert.NotAfter, warningDays, failOnExpired, time.Now(), &resp.Diagnostics)
+		}
+		if !plan.CsrId.IsNull() && !plan.CsrId.IsUnknown() {
+			r.checkSignedFromCsr(plan.CsrId.ValueString(), bundle, &resp.Diagnostics)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// The certificate ID is changed only if the certificate is rotated.
+	var state *sslCertificateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if state != nil {
+		outdated := plan.certificateOutdated(state)
+		if outdated {
+			// Plan to upload the certificate again, the fingerprints are
+			// refreshed from vendor after the upload.
+			plan.CrtMd5 = types.StringUnknown()
+			plan.KeyMd5 = types.StringUnknown()
+			plan.CaMd5 = types.StringUnknown()
+		}
+		if !plan.CsrId.IsNull() && plan.CsrId.Equal(state.CsrId) {
+			// The private key of the CSR is kept by vendor, uploading the
+			// certificate does not change it.
+			plan.KeyMd5 = state.KeyMd5
+		}
+		if plan.UpdateMode.ValueString() == certificateUpdateModeRotate && outdated {
+			plan.Id = types.StringUnknown()
+		} else {
+			plan.Id = state.Id
+		}
+	}
+	resp.Plan.Set(ctx, plan)
+}
+
+// checkSignedFromCsr validates that the leaf certificate of bundle is signed
+// from the CSR csrId by comparing the public keys. It only warns if the CSR
+// fails to be queried, the certificate is validated by vendor anyway.
+func (r *sslCertificateResource) checkSignedFromCsr(csrId string, bundle *certificateBundle, diags *diag.Diagnostics) {
+	queryCsrResponse, err := r.client.QueryCsr(csrId)
+	if err == nil && queryCsrResponse.Csr == nil {
+		err = errors.New("the CSR is empty")
+	}
+	if err != nil {
+		diags.AddAttributeWarning(path.Root("csr_id"), "Fail to Query CSR",
+			fmt.Sprintf("The certificate is not validated against the CSR %s: %v", csrId, err))
+		return
+	}
+	csr, err := parsePemCertificateRequest(*queryCsrResponse.Csr)
+	if err != nil {
+		diags.AddAttributeWarning(path.Root("csr_id"), "Fail to Parse CSR",
+			fmt.Sprintf("The certificate is not validated against the CSR %s: %v", csrId, err))
+		return
+	}
+
+	leaf := bundle.chain[0]
+	if !publicKeyEqual(leaf.cert.PublicKey, csr.PublicKey) {
+		diags.AddAttributeError(leaf.path, "[Validate Config] CSR mismatch",
+			fmt.Sprintf("The certificate %q is not signed from the CSR %s.", leaf.cert.Subject.String(), csrId))
+	}
+}
+
+// warnDestroyingUsedCertificate warns if the certificate to be destroyed is
+// still used by the domains, according to the refreshed related_domains.
+func (r *sslCertificateResource) warnDestroyingUsedCertificate(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var state *sslCertificateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if state == nil || state.RelatedDomains.IsNull() || state.RelatedDomains.IsUnknown() {
+		return
+	}
+
+	domains := make([]*cdnetworksapi.CertificateDomain, 0)
+	for _, element := range state.RelatedDomains.Elements() {
+		attributes := element.(types.Object).Attributes()
+		domains = append(domains, &cdnetworksapi.CertificateDomain{
+			DomainId:   attributes["domain_id"].(types.String).ValueStringPointer(),
+			DomainName: attributes["domain_name"].(types.String).ValueStringPointer(),
+		})
+	}
+	if len(domains) == 0 {
+		return
+	}
+
+	if state.ForceDetach.ValueBool() {
+		resp.Diagnostics.AddWarning("Certificate in use",
+			fmt.Sprintf("The certificate %q is used by the domains: %s. HTTPS of the domains will be disabled before the certificate is deleted.", state.Name.ValueString(), formatCertificateDomains(domains)))
+	} else {
+		resp.Diagnostics.AddWarning("Certificate in use",
+			fmt.Sprintf("The certificate %q is used by the domains: %s. Deleting it will fail unless the domains are detached from it earlier in the same apply, or force_detach is true.", state.Name.ValueString(), formatCertificateDomains(domains)))
+	}
+}
+
+// rotateCertificate uploads the certificate as a new one, points the domains
+// using the old certificate to the new one, and deletes the old certificate
+// after the domains are deployed. If the domains fail to be pointed to the new
+// certificate, they are pointed back and the new certificate is deleted.
+// Returns false if the rotation is not completed, state is updated to what
+// is at vendor.
+func (r *sslCertificateResource) rotateCertificate(ctx context.Context, plan, state *sslCertificateResourceModel, bundle *certificateBundle, diags *diag.Diagnostics) bool {
+	oldId := state.Id.ValueString()
+	queryCertificateResponse, err := r.client.QueryCertificate(oldId)
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Query Certificate", err.Error())
+		return false
+	}
+
+	// The name is unique, use a temporary name until the old certificate is
+	// deleted.
+	name := fmt.Sprintf("%s-%d", plan.Name.ValueString(), time.Now().Unix())
+	newId, err := r.uploadCertificate("", name, plan, bundle)
+	if err != nil {
+		diags.AddError("[API ERROR] Failed to Add Certificate", err.Error())
+		return false
+	}
+
+	originalSsls := make(map[string]*cdnetworksapi.Ssl)
+	err = r.repointDomains(ctx, queryCertificateResponse.RelatedDomains, oldId, newId, originalSsls)
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Rotate Certificate", err.Error())
+		r.rollbackRotation(newId, originalSsls, diags)
+		return false
+	}
+
+	// The domains are using the new certificate, track it from now on.
+	state.Id = types.StringValue(newId)
+	state.Name = types.StringValue(name)
+	state.SslCertificate = plan.SslCertificate
+	state.CertificateChain = plan.CertificateChain
+	state.SslKey = plan.SslKey
+	state.CsrId = plan.CsrId
+	// The fingerprints of the new certificate are refreshed later.
+	state.CrtMd5 = types.StringNull()
+	state.KeyMd5 = types.StringNull()
+	state.CaMd5 = types.StringNull()
+	deleteCertificateResponse, err := r.client.DeleteCertificateV2(oldId)
+	if err == nil && *deleteCertificateResponse.Code != "0" {
+		err = errors.New(*deleteCertificateResponse.Message)
+	}
+	if err != nil {
+		diags.AddError("[API ERROR] Failed to Del Certificate",
+			fmt.Sprintf("The domains are using the new certificate %s, but the old certificate %s fails to be deleted: %v", newId, oldId, err))
+		return false
+	}
+
+	_, err = r.uploadCertificate(newId, plan.Name.ValueString(), plan, bundle)
+	if err != nil {
+		diags.AddError("[API ERROR] Failed to Update Certificate",
+			fmt.Sprintf("The certificate is rotated, but fails to be renamed from %s: %v", name, err))
+		return false
+	}
+	return true
+}
+
+// repointDomains points the domains from the certificate oldId to the
+// certificate certificateId, either bound as the RSA or the ECDSA certificate,
+// and waits until they are deployed. The original SSL settings of the changed
+// domains are saved into originalSsls for rollback.
+func (r *sslCertificateResource) repointDomains(ctx context.Context, domains []*cdnetworksapi.CertificateDomain, oldId, certificateId string, originalSsls map[string]*cdnetworksapi.Ssl) error {
+	for _, domain := range domains {
+		domainId := *domain.DomainId
+		queryCdnDomainResponse, err := r.client.QueryCdnDomain(domainId)
+		if err != nil {
+			return fmt.Errorf("fail to query domain %s: %w", domainId, err)
+		}
+		original := queryCdnDomainResponse.Ssl
+		if original == nil {
+			continue
+		}
+		// The certificate may be bound to both slots, check them separately.
+		ssl := *original
+		repointed := false
+		if ssl.SslCertificateId != nil && *ssl.SslCertificateId == oldId {
+			ssl.SslCertificateId = &certificateId
+			repointed = true
+		}
+		if ssl.EccSslCertificateId != nil && *ssl.EccSslCertificateId == oldId {
+			ssl.EccSslCertificateId = &certificateId
+			repointed = true
+		}
+		if !repointed {
+			continue
+		}
+		_, err = r.client.UpdateCdnDomain(domainId, cdnetworksapi.UpdateCdnDomainRequest{
+			Ssl: &ssl,
+		})
+		if err != nil {
+			return fmt.Errorf("fail to update domain %s: %w", domainId, err)
+		}
+		originalSsls[domainId] = original
+	}
+
+	// The domains are deployed concurrently, wait for them one by one.
+	for domainId := range originalSsls {
+		err := utils.WaitForDomainDeployed(ctx, r.client, domainId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detachDomains disables HTTPS of the domains, so that the certificate used by
+// them can be deleted, and waits until they are deployed.
+func (r *sslCertificateResource) detachDomains(ctx context.Context, domains []*cdnetworksapi.CertificateDomain) error {
+	useSsl := false
+	for _, domain := range domains {
+		_, err := r.client.UpdateCdnDomain(*domain.DomainId, cdnetworksapi.UpdateCdnDomainRequest{
+			Ssl: &cdnetworksapi.Ssl{
+				UseSsl: &useSsl,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("fail to update domain %s: %w", *domain.DomainId, err)
+		}
+	}
+	for _, domain := range domains {
+		err := utils.WaitForDomainDeployed(ctx, r.client, *domain.DomainId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollbackRotation points the domains back to the original certificates, and
+// deletes the new certificate certificateId. It is done with a new context as
+// the rotation may fail due to timeout.
+func (r *sslCertificateResource) rollbackRotation(certificateId string, originalSsls map[string]*cdnetworksapi.Ssl, diags *diag.Diagnostics) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUpdateTimeout)
+	defer cancel()
+
+	failed := false
+	for domainId, ssl := range originalSsls {
+		_, err := r.client.UpdateCdnDomain(domainId, cdnetworksapi.UpdateCdnDomainRequest{
+			Ssl: ssl,
+		})
+		if err == nil {
+			err = utils.WaitForDomainDeployed(ctx, r.client, domainId)
+		}
+		if err != nil {
+			failed = true
+			diags.AddError("[API ERROR] Fail to Rollback Certificate Rotation",
+				fmt.Sprintf("Domain %s fails to be pointed back to certificate %s: %v", domainId, types.StringPointerValue(ssl.SslCertificateId).ValueString(), err))
+		}
+	}
+	if failed {
+		diags.AddError("[API ERROR] Fail to Rollback Certificate Rotation",
+			fmt.Sprintf("The new certificate %s is kept as it may be still used by the domains.", certificateId))
+		return
+	}
+
+	deleteCertificateResponse, err := r.client.DeleteCertificateV2(certificateId)
+	if err == nil && *deleteCertificateResponse.Code != "0" {
+		err = errors.New(*deleteCertificateResponse.Message)
+	}
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Rollback Certificate Rotation",
+			fmt.Sprintf("The new certificate %s fails to be deleted: %v", certificateId, err))
+	}
+}
+
 func (r *sslCertificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("ssl_certificate_id"), req, resp)
 }
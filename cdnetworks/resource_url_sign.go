@@ -4,6 +4,7 @@ import (
 	"context"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -12,22 +13,23 @@ import (
 )
 
 type urlSignResourceModel struct {
-	DomainId                 types.String `tfsdk:"domain_id"`
-	PrimaryKey               types.String `tfsdk:"primary_key"`
-	SecondaryKey             types.String `tfsdk:"secondary_key"`
-	LowerLimitExpiryTime     types.Int64  `tfsdk:"lower_limit_expiry_time"`
-	UpperLimitExpiryTime     types.Int64  `tfsdk:"upper_limit_expiry_time"`
-	PathPattern              types.String `tfsdk:"path_pattern"`
-	CipherCombination        types.String `tfsdk:"cipher_combination"`
-	CipherParam              types.String `tfsdk:"cipher_param"`
-	TimeParam                types.String `tfsdk:"time_param"`
-	TimeFormat               types.String `tfsdk:"time_format"`
-	RequestUrlStyle          types.String `tfsdk:"request_url_style"`
-	DstStyle                 types.Int64  `tfsdk:"dst_style"`
-	EncryptMethod            types.String `tfsdk:"encrypt_method"`
-	LogFormat                types.Bool   `tfsdk:"log_format"`
-	IgnoreUriSlash           types.Bool   `tfsdk:"ignore_uri_slash"`
-	IgnoreKeyAndTimePosition types.Bool   `tfsdk:"ignore_key_and_time_position"`
+	DomainId                 types.String   `tfsdk:"domain_id"`
+	PrimaryKey               types.String   `tfsdk:"primary_key"`
+	SecondaryKey             types.String   `tfsdk:"secondary_key"`
+	LowerLimitExpiryTime     types.Int64    `tfsdk:"lower_limit_expiry_time"`
+	UpperLimitExpiryTime     types.Int64    `tfsdk:"upper_limit_expiry_time"`
+	PathPattern              types.String   `tfsdk:"path_pattern"`
+	CipherCombination        types.String   `tfsdk:"cipher_combination"`
+	CipherParam              types.String   `tfsdk:"cipher_param"`
+	TimeParam                types.String   `tfsdk:"time_param"`
+	TimeFormat               types.String   `tfsdk:"time_format"`
+	RequestUrlStyle          types.String   `tfsdk:"request_url_style"`
+	DstStyle                 types.Int64    `tfsdk:"dst_style"`
+	EncryptMethod            types.String   `tfsdk:"encrypt_method"`
+	LogFormat                types.Bool     `tfsdk:"log_format"`
+	IgnoreUriSlash           types.Bool     `tfsdk:"ignore_uri_slash"`
+	IgnoreKeyAndTimePosition types.Bool     `tfsdk:"ignore_key_and_time_position"`
+	Timeouts                 timeouts.Value `tfsdk:"timeouts"`
 }
 
 type urlSignResource struct {
@@ -47,7 +49,7 @@ func (r *urlSignResource) Metadata(_ context.Context, req resource.MetadataReque
 	resp.TypeName = req.ProviderTypeName + "_url_sign"
 }
 
-func (r *urlSignResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *urlSignResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "The resource enable URL Signature for domain",
 		Attributes: map[string]schema.Attribute{
@@ -118,6 +120,9 @@ func (r *urlSignResource) Schema(_ context.Context, req resource.SchemaRequest,
 				Required:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
 	}
 }
 
@@ -125,7 +130,7 @@ func (r *urlSignResource) Configure(ctx context.Context, req resource.ConfigureR
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*cdnetworksapi.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 func (r *urlSignResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -135,6 +140,9 @@ func (r *urlSignResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	err := r.updateUrlSign(model)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Failed to Create URL Sign", err.Error())
@@ -151,6 +159,9 @@ func (r *urlSignResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	queryURLSignResponse, err := r.client.QueryURLSign(state.DomainId.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Failed to Read URL Sign", err.Error())
@@ -186,6 +197,9 @@ func (r *urlSignResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	err := r.updateUrlSign(plan)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Failed to Update URL Sign", err.Error())
@@ -206,6 +220,7 @@ func (r *urlSignResource) Update(ctx context.Context, req resource.UpdateRequest
 	state.LogFormat = plan.LogFormat
 	state.IgnoreUriSlash = plan.IgnoreUriSlash
 	state.IgnoreKeyAndTimePosition = plan.IgnoreKeyAndTimePosition
+	state.Timeouts = plan.Timeouts
 
 	resp.State.Set(ctx, state)
 }
@@ -218,6 +233,9 @@ func (r *urlSignResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	_, err := r.client.UpdateURLSign(model.DomainId.ValueString(), cdnetworksapi.UpdateURLSignRequest{
 		TimestampVisitControlRule: &cdnetworksapi.TimestampVisitControlRule{},
 	})
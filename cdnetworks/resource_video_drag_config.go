@@ -0,0 +1,205 @@
+package cdnetworks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+type videoDragConfigModel struct {
+	DomainId          types.String   `tfsdk:"domain_id"`
+	PathPattern       types.String   `tfsdk:"path_pattern"`
+	DragMode          types.String   `tfsdk:"drag_mode"`
+	StartFlag         types.String   `tfsdk:"start_flag"`
+	EndFlag           types.String   `tfsdk:"end_flag"`
+	WaitForDeployment types.Bool     `tfsdk:"wait_for_deployment"`
+	DeploymentStatus  types.String   `tfsdk:"deployment_status"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+type videoDragConfigResource struct {
+	client            *cdnetworksapi.Client
+	waitForDeployment bool
+}
+
+var (
+	_ resource.Resource                = &videoDragConfigResource{}
+	_ resource.ResourceWithConfigure   = &videoDragConfigResource{}
+	_ resource.ResourceWithImportState = &videoDragConfigResource{}
+)
+
+func NewVideoDragConfigResource() resource.Resource {
+	return &videoDragConfigResource{}
+}
+
+func (r *videoDragConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_video_drag_config"
+}
+
+func (r *videoDragConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Video drag configuration allows clients to seek MP4/FLV files by passing the start and end position as query string parameters.",
+		Attributes: map[string]schema.Attribute{
+			"domain_id": &schema.StringAttribute{
+				Description: "Domain ID",
+				Required:    true,
+			},
+			"wait_for_deployment": waitForDeploymentAttribute(),
+			"deployment_status":   deploymentStatusAttribute(),
+			"path_pattern": &schema.StringAttribute{
+				Description: "Url matching mode supports regular expression. E.g: .*\\.(mp4|flv)",
+				Required:    true,
+			},
+			"drag_mode": &schema.StringAttribute{
+				Description: `Drag mode, the optional value is
+                                    byTime: the start and end flags are treated as seconds.
+                                    byByte: the start and end flags are treated as bytes.`,
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("byTime", "byByte"),
+				},
+			},
+			"start_flag": &schema.StringAttribute{
+				Description: "The query string parameter name of the start position. Default: start",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("start"),
+			},
+			"end_flag": &schema.StringAttribute{
+				Description: "The query string parameter name of the end position. Default: end",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("end"),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+	}
+}
+
+func (r *videoDragConfigResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.waitForDeployment = data.waitForDeployment
+}
+
+func (r *videoDragConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *videoDragConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to set video_drag_config", err.Error())
+	}
+	resp.State.Set(ctx, model)
+}
+
+func (r *videoDragConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model *videoDragConfigModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	queryApiDomainResponse, err := r.client.QueryApiDomain(model.DomainId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to query video_drag_config", err.Error())
+		return
+	}
+	videodrags := queryApiDomainResponse.Videodrags
+	if videodrags == nil || videodrags.PathPattern == nil || *videodrags.PathPattern == "" {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	model.PathPattern = types.StringPointerValue(videodrags.PathPattern)
+	model.DragMode = types.StringPointerValue(videodrags.DragMode)
+	model.StartFlag = types.StringPointerValue(videodrags.StartFlag)
+	model.EndFlag = types.StringPointerValue(videodrags.EndFlag)
+	model.DeploymentStatus = refreshDeploymentStatus(r.client, model.DomainId.ValueString(), model.DeploymentStatus, &resp.Diagnostics)
+	resp.State.Set(ctx, model)
+}
+
+func (r *videoDragConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan *videoDragConfigModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	err := r.updateConfig(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to set video_drag_config", err.Error())
+		return
+	}
+	resp.State.Set(ctx, plan)
+}
+
+func (r *videoDragConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model *videoDragConfigModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, model.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
+	// Omitted fields are kept by vendor, clear them explicitly to disable video drag.
+	model.PathPattern = types.StringValue("")
+	model.DragMode = types.StringValue("")
+	model.StartFlag = types.StringValue("")
+	model.EndFlag = types.StringValue("")
+	err := r.updateConfig(ctx, model)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR]Fail to delete video_drag_config", err.Error())
+	}
+}
+
+func (r *videoDragConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
+}
+
+func (r *videoDragConfigResource) updateConfig(ctx context.Context, model *videoDragConfigModel) error {
+	if model == nil {
+		return errors.New("model is nil")
+	}
+	updateApiDomainRequest := cdnetworksapi.UpdateApiDomainRequest{
+		Videodrags: &cdnetworksapi.Videodrags{
+			PathPattern: model.PathPattern.ValueStringPointer(),
+			DragMode:    model.DragMode.ValueStringPointer(),
+			StartFlag:   model.StartFlag.ValueStringPointer(),
+			EndFlag:     model.EndFlag.ValueStringPointer(),
+		},
+	}
+	_, err := r.client.UpdateApiDomain(model.DomainId.ValueString(), updateApiDomainRequest)
+	if err != nil {
+		return err
+	}
+	model.DeploymentStatus, err = waitForDeployment(ctx, r.client, model.DomainId.ValueString(), shouldWaitForDeployment(model.WaitForDeployment, r.waitForDeployment))
+	return err
+}
@@ -0,0 +1,48 @@
+package cdnetworks
+
+import "fmt"
+
+// alignToPrior orders the rules refreshed from vendor as the rules in prior
+// state, so that reordering by vendor is not reported as a diff. The rules
+// found in prior are placed at their indexes in prior, and the others fill the
+// gaps in order. The gaps left by the rules deleted out of band are removed.
+func alignToPrior[T fmt.Stringer](prior, current []T) []T {
+	indexes := make(map[string][]int)
+	for i, rule := range prior {
+		indexes[rule.String()] = append(indexes[rule.String()], i)
+	}
+
+	size := len(prior)
+	if len(current) > size {
+		size = len(current)
+	}
+	slots := make([]T, size)
+	filled := make([]bool, size)
+	unmatched := make([]T, 0)
+	for _, rule := range current {
+		list := indexes[rule.String()]
+		if len(list) > 0 {
+			slots[list[0]] = rule
+			filled[list[0]] = true
+			indexes[rule.String()] = list[1:]
+		} else {
+			unmatched = append(unmatched, rule)
+		}
+	}
+	for i, j := 0, 0; i < size && j < len(unmatched); i++ {
+		if filled[i] {
+			continue
+		}
+		slots[i] = unmatched[j]
+		filled[i] = true
+		j++
+	}
+
+	aligned := make([]T, 0, len(current))
+	for i, rule := range slots {
+		if filled[i] {
+			aligned = append(aligned, rule)
+		}
+	}
+	return aligned
+}
@@ -0,0 +1,47 @@
+package cdnetworks
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func newTestCacheKeyRule(pathPattern string) *cacheKeyRuleModel {
+	return &cacheKeyRuleModel{
+		PathPattern: types.StringValue(pathPattern),
+		IgnoreCase:  types.BoolValue(false),
+		HeaderName:  types.StringValue("X-Device"),
+	}
+}
+
+func TestAlignToPrior(t *testing.T) {
+	a, b, c, d := newTestCacheKeyRule("/a/"), newTestCacheKeyRule("/b/"), newTestCacheKeyRule("/c/"), newTestCacheKeyRule("/d/")
+	tests := []struct {
+		name    string
+		prior   []*cacheKeyRuleModel
+		current []*cacheKeyRuleModel
+		expect  []*cacheKeyRuleModel
+	}{
+		{name: "reordered by vendor", prior: []*cacheKeyRuleModel{a, b, c}, current: []*cacheKeyRuleModel{c, a, b}, expect: []*cacheKeyRuleModel{a, b, c}},
+		{name: "deleted out of band", prior: []*cacheKeyRuleModel{a, b, c}, current: []*cacheKeyRuleModel{c}, expect: []*cacheKeyRuleModel{c}},
+		{name: "deleted in the middle", prior: []*cacheKeyRuleModel{a, b, c}, current: []*cacheKeyRuleModel{c, a}, expect: []*cacheKeyRuleModel{a, c}},
+		{name: "replaced out of band", prior: []*cacheKeyRuleModel{a, b, c}, current: []*cacheKeyRuleModel{d, c}, expect: []*cacheKeyRuleModel{d, c}},
+		{name: "added out of band", prior: []*cacheKeyRuleModel{a, b}, current: []*cacheKeyRuleModel{d, b, a}, expect: []*cacheKeyRuleModel{a, b, d}},
+		{name: "duplicated", prior: []*cacheKeyRuleModel{a, b, a}, current: []*cacheKeyRuleModel{a, a}, expect: []*cacheKeyRuleModel{a, a}},
+		{name: "imported", prior: nil, current: []*cacheKeyRuleModel{b, a}, expect: []*cacheKeyRuleModel{b, a}},
+		{name: "all deleted", prior: []*cacheKeyRuleModel{a, b}, current: []*cacheKeyRuleModel{}, expect: []*cacheKeyRuleModel{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aligned := alignToPrior(tt.prior, tt.current)
+			if len(aligned) != len(tt.expect) {
+				t.Fatalf("got %d rules, expect %d", len(aligned), len(tt.expect))
+			}
+			for i := range aligned {
+				if aligned[i] == nil || aligned[i].String() != tt.expect[i].String() {
+					t.Errorf("rule %d is %v, expect %s", i, aligned[i], tt.expect[i].PathPattern)
+				}
+			}
+		})
+	}
+}
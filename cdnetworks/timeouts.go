@@ -0,0 +1,36 @@
+package cdnetworks
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+const (
+	// Deployment normally takes ~10mins, but may take much longer at peak.
+	defaultCreateTimeout = 60 * time.Minute
+	defaultUpdateTimeout = 60 * time.Minute
+	defaultDeleteTimeout = 60 * time.Minute
+	defaultReadTimeout   = 5 * time.Minute
+)
+
+// timeoutsBlock is the timeouts block shared by all the resources.
+func timeoutsBlock(ctx context.Context) schema.Block {
+	return timeouts.Block(ctx, timeouts.Opts{
+		Create: true,
+		Read:   true,
+		Update: true,
+		Delete: true,
+	})
+}
+
+// withTimeout returns a copy of ctx which is done after the timeout returned
+// by getTimeout, e.g. model.Timeouts.Create, falling back to defaultTimeout.
+func withTimeout(ctx context.Context, getTimeout func(context.Context, time.Duration) (time.Duration, diag.Diagnostics), defaultTimeout time.Duration, diags *diag.Diagnostics) (context.Context, context.CancelFunc) {
+	timeout, d := getTimeout(ctx, defaultTimeout)
+	diags.Append(d...)
+	return context.WithTimeout(ctx, timeout)
+}
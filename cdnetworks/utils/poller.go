@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
+)
+
+const (
+	minPollInterval = 15 * time.Second
+	maxPollInterval = 2 * time.Minute
+)
+
+// domainStatus is the result of polling a domain, either the status or the
+// error of querying it. requestId is the id of the request returning the
+// status, for reporting the failures to vendor.
+type domainStatus struct {
+	status    string
+	requestId string
+	err       error
+}
+
+// domainStatusPoller polls the statuses of all the waited domains of a client
+// together, and fans out the statuses to the waiters. The domains are queried
+// through QueryApiDomainList in one call, only the domains missing from the
+// list are queried one by one. The polling interval is shortened when any
+// status changes or new waiter comes, and lengthened when nothing changes or
+// the query fails.
+type domainStatusPoller struct {
+	client *cdnetworksapi.Client
+
+	mutex        sync.Mutex
+	waiters      map[string]map[chan domainStatus]struct{}
+	lastStatuses map[string]string
+	interval     time.Duration
+	running      bool
+	wake         chan struct{}
+}
+
+var (
+	pollersMutex sync.Mutex
+	pollers      = make(map[*cdnetworksapi.Client]*domainStatusPoller)
+)
+
+// domainStatusPollerOf returns the poller shared by all the waiters of client.
+func domainStatusPollerOf(client *cdnetworksapi.Client) *domainStatusPoller {
+	pollersMutex.Lock()
+	defer pollersMutex.Unlock()
+
+	p, ok := pollers[client]
+	if !ok {
+		p = &domainStatusPoller{
+			client:       client,
+			waiters:      make(map[string]map[chan domainStatus]struct{}),
+			lastStatuses: make(map[string]string),
+			interval:     minPollInterval,
+			wake:         make(chan struct{}, 1),
+		}
+		pollers[client] = p
+	}
+	return p
+}
+
+// subscribe registers a waiter of domainId, the returned channel receives the
+// latest status of each poll until unsubscribe is called.
+func (p *domainStatusPoller) subscribe(domainId string) (statuses <-chan domainStatus, unsubscribe func()) {
+	ch := make(chan domainStatus, 1)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.waiters[domainId] == nil {
+		p.waiters[domainId] = make(map[chan domainStatus]struct{})
+	}
+	p.waiters[domainId][ch] = struct{}{}
+
+	// A change is just submitted for the domain, poll sooner.
+	p.interval = minPollInterval
+	if !p.running {
+		p.running = true
+		go p.run()
+	} else {
+		select {
+		case p.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return ch, func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+
+		delete(p.waiters[domainId], ch)
+		if len(p.waiters[domainId]) == 0 {
+			delete(p.waiters, domainId)
+			delete(p.lastStatuses, domainId)
+		}
+	}
+}
+
+// run polls until there is no waiter.
+func (p *domainStatusPoller) run() {
+	var lastPoll time.Time
+	for {
+		p.mutex.Lock()
+		next := lastPoll.Add(p.interval)
+		p.mutex.Unlock()
+
+		select {
+		case <-time.After(time.Until(next)):
+		case <-p.wake:
+			// The interval may be shortened, recalculate the next poll.
+			continue
+		}
+
+		domainIds := p.waitedDomainIds()
+		if len(domainIds) == 0 {
+			return
+		}
+		lastPoll = time.Now()
+		statuses, err := p.poll(domainIds)
+		p.publish(statuses, err)
+	}
+}
+
+// waitedDomainIds returns the domains being waited, marks the poller stopped
+// if there is none, so that the next subscribe starts a new one.
+func (p *domainStatusPoller) waitedDomainIds() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	domainIds := make([]string, 0, len(p.waiters))
+	for domainId := range p.waiters {
+		domainIds = append(domainIds, domainId)
+	}
+	if len(domainIds) == 0 {
+		p.running = false
+	}
+	return domainIds
+}
+
+func (p *domainStatusPoller) poll(domainIds []string) (map[string]domainStatus, error) {
+	queryApiDomainListResponse, err := p.client.QueryApiDomainList(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make(map[string]string)
+	for _, summary := range queryApiDomainListResponse.DomainSummaries {
+		if summary.DomainId != nil && summary.Status != nil {
+			listed[*summary.DomainId] = *summary.Status
+		}
+	}
+
+	statuses := make(map[string]domainStatus)
+	for _, domainId := range domainIds {
+		if status, ok := listed[domainId]; ok {
+			statuses[domainId] = domainStatus{status: status, requestId: queryApiDomainListResponse.RequestId}
+			continue
+		}
+		// The domain may be just created or deleted.
+		queryCdnDomainResponse, err := p.client.QueryCdnDomain(domainId)
+		if err != nil {
+			statuses[domainId] = domainStatus{err: err}
+		} else {
+			statuses[domainId] = domainStatus{status: *queryCdnDomainResponse.Status, requestId: queryCdnDomainResponse.RequestId}
+		}
+	}
+	return statuses, nil
+}
+
+// publish sends the statuses to the waiters and adapts the interval.
+func (p *domainStatusPoller) publish(statuses map[string]domainStatus, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if err != nil {
+		for _, chs := range p.waiters {
+			for ch := range chs {
+				send(ch, domainStatus{err: err})
+			}
+		}
+		// Probably rate limited, slow down.
+		p.interval = minDuration(p.interval*2, maxPollInterval)
+		return
+	}
+
+	changed := false
+	for domainId, status := range statuses {
+		chs, ok := p.waiters[domainId]
+		if !ok {
+			continue
+		}
+		if last, ok := p.lastStatuses[domainId]; !ok || last != status.status {
+			changed = true
+		}
+		p.lastStatuses[domainId] = status.status
+		for ch := range chs {
+			send(ch, status)
+		}
+	}
+	if changed {
+		p.interval = minPollInterval
+	} else {
+		p.interval = minDuration(p.interval*3/2, maxPollInterval)
+	}
+}
+
+// send replaces the unreceived status in ch with the latest one.
+func send(ch chan domainStatus, status domainStatus) {
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- status
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
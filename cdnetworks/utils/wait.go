@@ -1,55 +1,346 @@
 package utils
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/myklst/terraform-provider-st-cdnetworks/cdnetworksapi"
 )
 
-func WaitForDomainDeployed(client *cdnetworksapi.Client, domainId string) error {
-	checkStatus := func() error {
-		// QueryCdnDomains ratelimit is 300/s, normally take ~10mins to successfully update.
-		queryCdnDomainResponse, err := client.QueryCdnDomain(domainId)
-		if err != nil {
-			return err
+// progressLogInterval is the interval of logging the progress of the waits.
+const progressLogInterval = time.Minute
+
+// TimeoutError is returned by the wait functions when ctx is done before the
+// waiting condition is met.
+type TimeoutError struct {
+	WaitingFor string
+	LastStatus string
+	Err        error
+}
+
+func (e *TimeoutError) Error() string {
+	lastStatus := e.LastStatus
+	if lastStatus == "" {
+		lastStatus = "unknown"
+	}
+	return fmt.Sprintf("timeout while waiting for %s, last observed status: %s (%v)", e.WaitingFor, lastStatus, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// retryUntilDone retries checkStatus with b until it succeeds, fails
+// permanently or ctx is done. checkStatus returns the observed status, which
+// is reported in TimeoutError.
+func retryUntilDone(ctx context.Context, b backoff.BackOff, waitingFor string, checkStatus func() (string, error)) error {
+	start := time.Now()
+	lastLog := start
+	var lastStatus string
+	err := backoff.Retry(func() error {
+		status, err := checkStatus()
+		if status != "" {
+			lastStatus = status
+		} else if err != nil {
+			lastStatus = err.Error()
+		}
+		if err != nil && time.Since(lastLog) >= progressLogInterval {
+			lastLog = time.Now()
+			tflog.Info(ctx, "Still waiting for "+waitingFor, map[string]interface{}{
+				"elapsed":     time.Since(start).Round(time.Second).String(),
+				"last_status": lastStatus,
+			})
+		}
+		return err
+	}, backoff.WithContext(b, ctx))
+	if err != nil && ctx.Err() != nil {
+		return &TimeoutError{
+			WaitingFor: waitingFor,
+			LastStatus: lastStatus,
+			Err:        ctx.Err(),
+		}
+	}
+	return err
+}
+
+// DeploymentError is returned when the changes of a domain fail to be
+// deployed, or the domain can not be queried anymore. Message and RequestId
+// come from vendor, and are required by vendor to investigate the failure.
+type DeploymentError struct {
+	DomainId  string
+	Status    string
+	Message   string
+	RequestId string
+}
+
+func (e *DeploymentError) Error() string {
+	msg := "deployment of domain " + e.DomainId + " failed"
+	if e.Status != "" {
+		msg += ", status: " + e.Status
+	}
+	msg += ", message: " + e.Message
+	if e.RequestId != "" {
+		msg += ", request-id: " + e.RequestId
+	}
+	return msg
+}
+
+// newDeploymentError converts the error of querying the domain to
+// DeploymentError, keeps the vendor message and request id if any.
+func newDeploymentError(domainId string, err error) *DeploymentError {
+	var errorResponse *cdnetworksapi.ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return &DeploymentError{
+			DomainId:  domainId,
+			Message:   errorResponse.ResponseCode + ": " + errorResponse.ResponseMessage,
+			RequestId: errorResponse.RequestId,
+		}
+	}
+	return &DeploymentError{
+		DomainId: domainId,
+		Message:  err.Error(),
+	}
+}
+
+type deploymentState int
+
+const (
+	deploymentPending deploymentState = iota
+	deploymentSucceeded
+	deploymentFailed
+	deploymentUnknown
+)
+
+// classifyDomainStatus classifies the status of a domain, returns the reason
+// for the failed statuses.
+func classifyDomainStatus(status string) (state deploymentState, reason string) {
+	switch status {
+	case cdnetworksapi.DomainStatusDeployed:
+		return deploymentSucceeded, ""
+	case cdnetworksapi.DomainStatusInProgress:
+		return deploymentPending, ""
+	case cdnetworksapi.DomainStatusReviewing:
+		return deploymentFailed, "the changes are being reviewed, please contact to vendor"
+	case cdnetworksapi.DomainStatusFailed:
+		return deploymentFailed, "the changes fail to be deployed, please contact to vendor"
+	default:
+		return deploymentUnknown, ""
+	}
+}
+
+// isRetryableError returns true if the error of querying is temporary, i.e.
+// network errors, rate limiting and server errors.
+func isRetryableError(err error) bool {
+	var errorResponse *cdnetworksapi.ErrorResponse
+	if !errors.As(err, &errorResponse) {
+		return !IsNotFoundError(err)
+	}
+	return errorResponse.StatusCode == http.StatusTooManyRequests || errorResponse.StatusCode >= 500
+}
+
+// IsNotFoundError returns true if the error is caused by querying the
+// resource not existing at vendor.
+func IsNotFoundError(err error) bool {
+	var errorResponse *cdnetworksapi.ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return errorResponse.StatusCode == http.StatusNotFound
+	}
+	return strings.Contains(err.Error(), "status: 404")
+}
+
+// waitForDomainStatus waits until done returns true or error for the status
+// of the domain polled by the shared poller. The progress is logged every
+// progressLogInterval and whenever the status changes.
+func waitForDomainStatus(ctx context.Context, client *cdnetworksapi.Client, domainId, waitingFor string, done func(domainStatus) (bool, error)) error {
+	statuses, unsubscribe := domainStatusPollerOf(client).subscribe(domainId)
+	defer unsubscribe()
+
+	start := time.Now()
+	progress := time.NewTicker(progressLogInterval)
+	defer progress.Stop()
+
+	var lastStatus string
+	for {
+		select {
+		case <-ctx.Done():
+			return &TimeoutError{
+				WaitingFor: waitingFor,
+				LastStatus: lastStatus,
+				Err:        ctx.Err(),
+			}
+		case <-progress.C:
+			tflog.Info(ctx, "Still waiting for "+waitingFor, map[string]interface{}{
+				"elapsed":     time.Since(start).Round(time.Second).String(),
+				"last_status": lastStatus,
+			})
+		case status := <-statuses:
+			if status.err == nil && status.status != lastStatus {
+				tflog.Debug(ctx, "Status of domain changed", map[string]interface{}{
+					"domain_id": domainId,
+					"status":    status.status,
+					"elapsed":   time.Since(start).Round(time.Second).String(),
+				})
+			}
+			if status.err != nil {
+				lastStatus = status.err.Error()
+			} else {
+				lastStatus = status.status
+			}
+			ok, err := done(status)
+			if err != nil {
+				return err
+			}
+			if ok {
+				tflog.Info(ctx, "Finished waiting for "+waitingFor, map[string]interface{}{
+					"elapsed": time.Since(start).Round(time.Second).String(),
+				})
+				return nil
+			}
 		}
+	}
+}
 
-		if *queryCdnDomainResponse.Status == "Deployed" {
-			return nil
+func WaitForDomainDeployed(ctx context.Context, client *cdnetworksapi.Client, domainId string) error {
+	// QueryCdnDomains ratelimit is 300/s, normally take ~10mins to successfully update.
+	return waitForDomainStatus(ctx, client, domainId, "domain "+domainId+" to be deployed", func(status domainStatus) (bool, error) {
+		if status.err != nil {
+			if isRetryableError(status.err) {
+				tflog.Warn(ctx, "Fail to query status of domain, retrying", map[string]interface{}{
+					"domain_id": domainId,
+					"error":     status.err.Error(),
+				})
+				return false, nil
+			}
+			return false, newDeploymentError(domainId, status.err)
 		}
 
-		if *queryCdnDomainResponse.Status == "Reviewing" {
-			return backoff.Permanent(fmt.Errorf("status is in reviewing, please contact to vendor"))
+		state, reason := classifyDomainStatus(status.status)
+		switch state {
+		case deploymentSucceeded:
+			return true, nil
+		case deploymentFailed:
+			return false, &DeploymentError{
+				DomainId:  domainId,
+				Status:    status.status,
+				Message:   reason,
+				RequestId: status.requestId,
+			}
+		case deploymentUnknown:
+			// Keep waiting as it may be a new intermediate status, the wait is
+			// still bounded by the timeout.
+			tflog.Warn(ctx, "Unknown status of domain, treated as in progress", map[string]interface{}{
+				"domain_id":  domainId,
+				"status":     status.status,
+				"request_id": status.requestId,
+			})
 		}
+		return false, nil
+	})
+}
+
+func WaitForDomainDeleted(ctx context.Context, client *cdnetworksapi.Client, domainId string) error {
+	return waitForDomainStatus(ctx, client, domainId, "domain "+domainId+" to be deleted", func(status domainStatus) (bool, error) {
+		if status.err == nil {
+			return false, nil
+		}
+		if IsNotFoundError(status.err) {
+			return true, nil
+		}
+		if isRetryableError(status.err) {
+			tflog.Warn(ctx, "Fail to query status of domain, retrying", map[string]interface{}{
+				"domain_id": domainId,
+				"error":     status.err.Error(),
+			})
+			return false, nil
+		}
+		return false, newDeploymentError(domainId, status.err)
+	})
+}
 
-		return errors.New("deployment is in progress")
+// WaitForPurgeCompleted waits until every url/dir of the purge task is
+// processed, returns the details of the urls/dirs failed to be purged.
+func WaitForPurgeCompleted(ctx context.Context, client *cdnetworksapi.Client, itemId string) ([]*cdnetworksapi.ContentTaskDetail, error) {
+	return waitForContentTaskCompleted(ctx, "purge task "+itemId+" to be completed", func() ([]*cdnetworksapi.ContentTaskDetail, error) {
+		queryPurgeStatusResponse, err := client.QueryPurgeStatus(itemId)
+		return queryPurgeStatusResponse.ResultDetail, err
+	})
+}
+
+// WaitForPrefetchCompleted waits until every url of the prefetch task is
+// processed, returns the details of the urls failed to be prefetched.
+func WaitForPrefetchCompleted(ctx context.Context, client *cdnetworksapi.Client, itemId string) ([]*cdnetworksapi.ContentTaskDetail, error) {
+	return waitForContentTaskCompleted(ctx, "prefetch task "+itemId+" to be completed", func() ([]*cdnetworksapi.ContentTaskDetail, error) {
+		queryPrefetchStatusResponse, err := client.QueryPrefetchStatus(itemId)
+		return queryPrefetchStatusResponse.ResultDetail, err
+	})
+}
+
+func waitForContentTaskCompleted(ctx context.Context, waitingFor string, queryDetails func() ([]*cdnetworksapi.ContentTaskDetail, error)) (failed []*cdnetworksapi.ContentTaskDetail, err error) {
+	checkStatus := func() (string, error) {
+		details, err := queryDetails()
+		if err != nil {
+			return "", err
+		}
+
+		if len(details) == 0 {
+			return cdnetworksapi.ContentTaskStatusInProgress, errors.New("task is in progress")
+		}
+
+		failed = make([]*cdnetworksapi.ContentTaskDetail, 0)
+		pending := 0
+		for _, detail := range details {
+			if detail.Status == nil {
+				pending++
+				continue
+			}
+			switch *detail.Status {
+			case cdnetworksapi.ContentTaskStatusSuccess:
+			case cdnetworksapi.ContentTaskStatusFailed:
+				failed = append(failed, detail)
+			default:
+				pending++
+			}
+		}
+		if pending > 0 {
+			return fmt.Sprintf("%d of %d in progress", pending, len(details)), errors.New("task is in progress")
+		}
+		return cdnetworksapi.ContentTaskStatusSuccess, nil
 	}
 
 	r := backoff.NewExponentialBackOff()
-	r.InitialInterval = 30 * time.Second
-	r.MaxElapsedTime = 0 // set as infinite retries.
+	r.InitialInterval = 10 * time.Second
+	r.MaxElapsedTime = 0 // retry until ctx is done.
 
-	return backoff.Retry(checkStatus, r)
+	err = retryUntilDone(ctx, r, waitingFor, checkStatus)
+	return
 }
 
-func WaitForDomainDeleted(client *cdnetworksapi.Client, domainId string) error {
-	checkStatus := func() error {
-		_, err := client.QueryCdnDomain(domainId)
+// WaitForIPv6Configured waits until the IPv6 configuration of the domain is
+// changed to enableIpv6.
+func WaitForIPv6Configured(ctx context.Context, client *cdnetworksapi.Client, domainId string, enableIpv6 bool) error {
+	checkStatus := func() (string, error) {
+		queryIPv6Response, err := client.QueryIPv6Config(domainId)
 		if err != nil {
-			if strings.Contains(err.Error(), "404") {
-				return nil
-			}
-			return err
+			return "", err
+		}
+
+		status := fmt.Sprintf("use-ipv6=%t", *queryIPv6Response.UseIpv6)
+		if *queryIPv6Response.UseIpv6 == enableIpv6 {
+			return status, nil
 		}
-		return errors.New("deployment is in progress")
+
+		return status, errors.New("deployment is in progress")
 	}
 
 	r := backoff.NewExponentialBackOff()
-	r.MaxElapsedTime = 0 // set as infinite retries.
+	r.InitialInterval = 10 * time.Second
+	r.MaxElapsedTime = 0 // retry until ctx is done.
 
-	return backoff.Retry(checkStatus, r)
+	return retryUntilDone(ctx, r, "IPv6 configuration of domain "+domainId+" to be deployed", checkStatus)
 }
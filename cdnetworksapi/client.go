@@ -20,7 +20,13 @@ import (
 // Client
 ////////////////////////////////////////////////////////////////////////////////
 
-const ApiEndpoint = "https://api.cdnetworks.com"
+const (
+	ApiEndpoint = "https://api.cdnetworks.com"
+
+	// RequestIdHeader is the response header of the request id, which is
+	// required by vendor to investigate the issues.
+	RequestIdHeader = "X-Cnc-Request-Id"
+)
 
 type Client struct {
 	Username   string
@@ -209,7 +215,7 @@ func (c *Client) doApiRequestWithEncoding(encoding Encoding, request Request, re
 		return nil, err
 	}
 	if res.StatusCode < 200 || res.StatusCode > 299 {
-		requestId := res.Header.Get("X-Cnc-Request-Id")
+		requestId := res.Header.Get(RequestIdHeader)
 		errorResponse := &ErrorResponse{
 			Url:         res.Url,
 			RequestId:   requestId,
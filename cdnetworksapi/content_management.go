@@ -0,0 +1,177 @@
+package cdnetworksapi
+
+import (
+	"strconv"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// Purge
+////////////////////////////////////////////////////////////////////////////////
+
+const (
+	// Status of each url/dir of a purge or prefetch task.
+	ContentTaskStatusSuccess    = "SUCCESS"
+	ContentTaskStatusFailed     = "FAIL"
+	ContentTaskStatusInProgress = "WAIT"
+)
+
+// SubmitPurge 提交缓存刷新(URL/目录/正则)
+
+type SubmitPurgeRequest struct {
+	Urls    []*string `json:"urls,omitempty" xml:"urls,omitempty"`
+	Dirs    []*string `json:"dirs,omitempty" xml:"dirs,omitempty"`
+	Regexes []*string `json:"regexes,omitempty" xml:"regexes,omitempty"`
+}
+
+type SubmitPurgeResponse struct {
+	Code    *int    `json:"Code" xml:"Code"`
+	Message *string `json:"Message" xml:"Message"`
+	ItemId  *string `json:"itemId" xml:"itemId"`
+}
+
+func (c *Client) SubmitPurge(request SubmitPurgeRequest) (response SubmitPurgeResponse, err error) {
+	_, err = c.DoJsonApiRequest(Request{
+		Method: HttpPost,
+		Path:   "/ccm/purge/ItemIdReceiver",
+		Body:   request,
+	}, &response)
+	if err == nil {
+		err = checkContentResponseCode(response.Code, response.Message)
+	}
+	return
+}
+
+// QueryPurgeStatus 查询缓存刷新任务状态
+
+type QueryPurgeStatusRequest struct {
+	ItemId *string `json:"itemId,omitempty" xml:"itemId,omitempty"`
+}
+
+type ContentTaskDetail struct {
+	Url          *string `json:"url" xml:"url"`
+	Type         *string `json:"type" xml:"type"`
+	Status       *string `json:"status" xml:"status"`
+	CreatedTime  *string `json:"createdTime" xml:"createdTime"`
+	FinishedTime *string `json:"finishedTime" xml:"finishedTime"`
+}
+
+type QueryPurgeStatusResponse struct {
+	Code         *int                 `json:"Code" xml:"Code"`
+	Message      *string              `json:"Message" xml:"Message"`
+	ResultDetail []*ContentTaskDetail `json:"resultDetail" xml:"resultDetail"`
+}
+
+func (c *Client) QueryPurgeStatus(itemId string) (response QueryPurgeStatusResponse, err error) {
+	_, err = c.DoJsonApiRequest(Request{
+		Method: HttpPost,
+		Path:   "/ccm/purge/ItemIdQuery",
+		Body:   QueryPurgeStatusRequest{ItemId: &itemId},
+	}, &response)
+	if err == nil {
+		err = checkContentResponseCode(response.Code, response.Message)
+	}
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Prefetch
+////////////////////////////////////////////////////////////////////////////////
+
+// PrefetchUrlsLimit is the maximum number of urls in a single prefetch request.
+const PrefetchUrlsLimit = 400
+
+// SubmitPrefetch 提交预取
+
+type SubmitPrefetchRequest struct {
+	Urls []*string `json:"urls,omitempty" xml:"urls,omitempty"`
+}
+
+type SubmitPrefetchResponse struct {
+	Code    *int    `json:"Code" xml:"Code"`
+	Message *string `json:"Message" xml:"Message"`
+	ItemId  *string `json:"itemId" xml:"itemId"`
+}
+
+func (c *Client) SubmitPrefetch(request SubmitPrefetchRequest) (response SubmitPrefetchResponse, err error) {
+	_, err = c.DoJsonApiRequest(Request{
+		Method: HttpPost,
+		Path:   "/ccm/fetch/ItemIdReceiver",
+		Body:   request,
+	}, &response)
+	if err == nil {
+		err = checkContentResponseCode(response.Code, response.Message)
+	}
+	return
+}
+
+// QueryPrefetchStatus 查询预取任务状态
+
+type QueryPrefetchStatusRequest struct {
+	ItemId *string `json:"itemId,omitempty" xml:"itemId,omitempty"`
+}
+
+type QueryPrefetchStatusResponse struct {
+	Code         *int                 `json:"Code" xml:"Code"`
+	Message      *string              `json:"Message" xml:"Message"`
+	ResultDetail []*ContentTaskDetail `json:"resultDetail" xml:"resultDetail"`
+}
+
+func (c *Client) QueryPrefetchStatus(itemId string) (response QueryPrefetchStatusResponse, err error) {
+	_, err = c.DoJsonApiRequest(Request{
+		Method: HttpPost,
+		Path:   "/ccm/fetch/ItemIdQuery",
+		Body:   QueryPrefetchStatusRequest{ItemId: &itemId},
+	}, &response)
+	if err == nil {
+		err = checkContentResponseCode(response.Code, response.Message)
+	}
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Quota
+////////////////////////////////////////////////////////////////////////////////
+
+// QueryContentQuota 查询刷新及预取每日额度
+
+type QueryContentQuotaResponse struct {
+	Code           *int    `json:"Code" xml:"Code"`
+	Message        *string `json:"Message" xml:"Message"`
+	UrlQuota       *int64  `json:"urlQuota" xml:"urlQuota"`
+	UrlRemain      *int64  `json:"urlRemain" xml:"urlRemain"`
+	DirQuota       *int64  `json:"dirQuota" xml:"dirQuota"`
+	DirRemain      *int64  `json:"dirRemain" xml:"dirRemain"`
+	PrefetchQuota  *int64  `json:"fetchQuota" xml:"fetchQuota"`
+	PrefetchRemain *int64  `json:"fetchRemain" xml:"fetchRemain"`
+}
+
+func (c *Client) QueryContentQuota() (response QueryContentQuotaResponse, err error) {
+	_, err = c.DoJsonApiRequest(Request{
+		Method: HttpPost,
+		Path:   "/ccm/purge/GetPurgeQuota",
+		Body:   struct{}{},
+	}, &response)
+	if err == nil {
+		err = checkContentResponseCode(response.Code, response.Message)
+	}
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helper
+////////////////////////////////////////////////////////////////////////////////
+
+// checkContentResponseCode returns an error if the content management API
+// responds HTTP 200 with a business code other than 1.
+func checkContentResponseCode(code *int, message *string) error {
+	if code == nil || *code == 1 {
+		return nil
+	}
+	errorResponse := &ErrorResponse{
+		ResponseCode: strconv.Itoa(*code),
+	}
+	if message != nil {
+		errorResponse.ResponseMessage = *message
+	}
+	return errorResponse
+}
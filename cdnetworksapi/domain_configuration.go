@@ -728,6 +728,127 @@ func (c *Client) UpdateIgnoreProtocol(domainId string, request UpdateIgnoreProto
 	return
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// Cache Key Config
+////////////////////////////////////////////////////////////////////////////////
+
+type CacheKeyRule struct {
+	DataId      *string `json:"data-id,omitempty" xml:"data-id,omitempty"`
+	PathPattern *string `json:"path-pattern,omitempty" xml:"path-pattern,omitempty"`
+	IgnoreCase  *bool   `json:"ignore-case,omitempty" xml:"ignore-case,omitempty"`
+	HeaderName  *string `json:"header-name,omitempty" xml:"header-name,omitempty"`
+}
+
+// QueryCacheKeyConfig 查询缓存键配置
+
+type QueryCacheKeyConfigResponse struct {
+	DomainId      *string         `json:"domain-id" xml:"domain-id"`
+	DomainName    *string         `json:"domain-name" xml:"domain-name"`
+	CacheKeyRules []*CacheKeyRule `json:"cache-key-rules" xml:"cache-key-rules>cache-key-rule"`
+}
+
+func (c *Client) QueryCacheKeyConfig(domainId string) (response QueryCacheKeyConfigResponse, err error) {
+	_, err = c.DoXmlApiRequest(Request{
+		Method: HttpGet,
+		Path:   "/api/config/cachekey/" + domainId,
+	}, &response)
+	return
+}
+
+// UpdateCacheKeyConfig 修改缓存键配置
+
+type UpdateCacheKeyConfigRequest struct {
+	XMLName       xml.Name        `json:"-" xml:"domain"`
+	CacheKeyRules []*CacheKeyRule `json:"cache-key-rules,omitempty" xml:"cache-key-rules>cache-key-rule"`
+}
+
+type UpdateCacheKeyConfigResponse struct {
+	Code    *string `json:"code" xml:"code"`
+	Message *string `json:"message" xml:"message"`
+}
+
+func (c *Client) UpdateCacheKeyConfig(domainId string, request UpdateCacheKeyConfigRequest) (response UpdateCacheKeyConfigResponse, err error) {
+	_, err = c.DoXmlApiRequest(Request{
+		Method: HttpPut,
+		Path:   "/api/config/cachekey/" + domainId,
+		Body:   request,
+	}, &response)
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Range And Follow Redirect
+////////////////////////////////////////////////////////////////////////////////
+
+// UpdateRangeAndFollowConfig 修改分片回源及301/302跟随配置
+
+type UpdateRangeAndFollowConfigRequest struct {
+	XMLName   xml.Name `json:"-" xml:"domain"`
+	UseRange  *bool    `json:"useRange,omitempty" xml:"useRange,omitempty"`
+	Follow301 *bool    `json:"follow301,omitempty" xml:"follow301,omitempty"`
+	Follow302 *bool    `json:"follow302,omitempty" xml:"follow302,omitempty"`
+}
+
+type UpdateRangeAndFollowConfigResponse struct {
+	Code    *string `json:"code" xml:"code"`
+	Message *string `json:"message" xml:"message"`
+}
+
+func (c *Client) UpdateRangeAndFollowConfig(domainId string, request UpdateRangeAndFollowConfigRequest) (response UpdateRangeAndFollowConfigResponse, err error) {
+	_, err = c.DoXmlApiRequest(Request{
+		Method: HttpPut,
+		Path:   "/cdnw/api/domain/" + domainId,
+		Body:   request,
+	}, &response)
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Origin Shield
+////////////////////////////////////////////////////////////////////////////////
+
+type OriginShieldConfig struct {
+	ShieldRegion *string `json:"shield-region,omitempty" xml:"shield-region,omitempty"`
+	ShieldNodes  *string `json:"shield-nodes,omitempty" xml:"shield-nodes,omitempty"`
+}
+
+// QueryOriginShieldConfig 查询回源中间层配置
+
+type QueryOriginShieldConfigResponse struct {
+	DomainId           *string             `json:"domain-id" xml:"domain-id"`
+	DomainName         *string             `json:"domain-name" xml:"domain-name"`
+	OriginShieldConfig *OriginShieldConfig `json:"origin-shield" xml:"origin-shield"`
+}
+
+func (c *Client) QueryOriginShieldConfig(domainId string) (response QueryOriginShieldConfigResponse, err error) {
+	_, err = c.DoXmlApiRequest(Request{
+		Method: HttpGet,
+		Path:   "/api/config/originshield/" + domainId,
+	}, &response)
+	return
+}
+
+// UpdateOriginShieldConfig 修改回源中间层配置
+
+type UpdateOriginShieldConfigRequest struct {
+	XMLName            xml.Name            `json:"-" xml:"domain"`
+	OriginShieldConfig *OriginShieldConfig `json:"origin-shield,omitempty" xml:"origin-shield,omitempty"`
+}
+
+type UpdateOriginShieldConfigResponse struct {
+	Code    *string `json:"code" xml:"code"`
+	Message *string `json:"message" xml:"message"`
+}
+
+func (c *Client) UpdateOriginShieldConfig(domainId string, request UpdateOriginShieldConfigRequest) (response UpdateOriginShieldConfigResponse, err error) {
+	_, err = c.DoXmlApiRequest(Request{
+		Method: HttpPut,
+		Path:   "/api/config/originshield/" + domainId,
+		Body:   request,
+	}, &response)
+	return
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Ban Urls
 ////////////////////////////////////////////////////////////////////////////////
@@ -791,9 +912,9 @@ type AdvOriginConfig struct {
 }
 
 type AdvOriginConfigs struct {
-	DetectUrl       *string          `json:"detect-url,omitempty" xml:"detect-url,omitempty"`
-	DetectPeriod    *int             `json:"detect-period,omitempty" xml:"detect-period,omitempty"`
-	AdvOriginConfig *AdvOriginConfig `json:"adv-origin-config,omitempty" xml:"adv-origin-config,omitempty"`
+	DetectUrl       *string            `json:"detect-url,omitempty" xml:"detect-url,omitempty"`
+	DetectPeriod    *int               `json:"detect-period,omitempty" xml:"detect-period,omitempty"`
+	AdvOriginConfig []*AdvOriginConfig `json:"adv-origin-config,omitempty" xml:"adv-origin-config,omitempty"`
 }
 
 type OriginConfigInApiDomain struct {
@@ -803,10 +924,14 @@ type OriginConfigInApiDomain struct {
 	AdvOriginConfigs        *AdvOriginConfigs `json:"adv-origin-configs,omitempty" xml:"adv-origin-configs,omitempty"`
 }
 
+// Ssl is the HTTPS setting of the domain. EccSslCertificateId is the ECDSA
+// certificate bound together with the RSA certificate SslCertificateId, if
+// dual certificates are supported by the domain.
 type Ssl struct {
-	UseSsl           *bool   `json:"use-ssl,omitempty" xml:"use-ssl,omitempty"`
-	UseForSni        *bool   `json:"use-for-sni,omitempty" xml:"use-for-sni,omitempty"`
-	SslCertificateId *string `json:"ssl-certificate-id,omitempty" xml:"ssl-certificate-id,omitempty"`
+	UseSsl              *bool   `json:"use-ssl,omitempty" xml:"use-ssl,omitempty"`
+	UseForSni           *bool   `json:"use-for-sni,omitempty" xml:"use-for-sni,omitempty"`
+	SslCertificateId    *string `json:"ssl-certificate-id,omitempty" xml:"ssl-certificate-id,omitempty"`
+	EccSslCertificateId *string `json:"ecc-ssl-certificate-id,omitempty" xml:"ecc-ssl-certificate-id,omitempty"`
 }
 
 type ErrorPageRule struct {
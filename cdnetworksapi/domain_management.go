@@ -8,6 +8,16 @@ import (
 // Domain Property
 ////////////////////////////////////////////////////////////////////////////////
 
+const (
+	// Deployment status of a domain. The changes of a domain are reviewed by
+	// vendor if they are considered risky, and need vendor's action to
+	// continue.
+	DomainStatusDeployed   = "Deployed"
+	DomainStatusInProgress = "InProgress"
+	DomainStatusReviewing  = "Reviewing"
+	DomainStatusFailed     = "Failed"
+)
+
 type CacheBehavior struct {
 	PathPattern        *string `json:"path-pattern" xml:"path-pattern"`
 	CacheTtl           *int64  `json:"cache-ttl" xml:"cache-ttl"`
@@ -66,13 +76,18 @@ type QueryCdnDomainResponse struct {
 	OriginConfig     *OriginConfig    `json:"origin-config" xml:"origin-config"`
 	Ssl              *Ssl             `json:"ssl" xml:"ssl"`
 	CacheBehaviors   []*CacheBehavior `json:"cache-behaviors" xml:"cache-behaviors>cache-behavior"`
+	RequestId        string           `json:"-" xml:"-"`
 }
 
 func (c *Client) QueryCdnDomain(domainId string) (response QueryCdnDomainResponse, err error) {
-	_, err = c.DoXmlApiRequest(Request{
+	res, err := c.DoXmlApiRequest(Request{
 		Method: HttpGet,
 		Path:   "/cdnw/api/domain/" + domainId,
 	}, &response)
+	if err != nil {
+		return
+	}
+	response.RequestId = res.Header.Get(RequestIdHeader)
 	return
 }
 
@@ -100,6 +115,7 @@ func (c *Client) QueryCdnDomain(domainName string) (response DomainSummary, err
 
 type QueryApiDomainListResponse struct {
 	DomainSummaries []*DomainSummary `json:"domain-summary" xml:"domain-summary"`
+	RequestId       string           `json:"-" xml:"-"`
 }
 
 func (c *Client) QueryApiDomainList(cnameLabel *string) (response QueryApiDomainListResponse, err error) {
@@ -107,11 +123,15 @@ func (c *Client) QueryApiDomainList(cnameLabel *string) (response QueryApiDomain
 	if cnameLabel != nil {
 		query = map[string]string{"cname-label": *cnameLabel}
 	}
-	_, err = c.DoXmlApiRequest(Request{
+	res, err := c.DoXmlApiRequest(Request{
 		Method: HttpGet,
 		Path:   "/api/domain",
 		Query:  query,
 	}, &response)
+	if err != nil {
+		return
+	}
+	response.RequestId = res.Header.Get(RequestIdHeader)
 	return
 }
 
@@ -2,6 +2,7 @@ package cdnetworksapi
 
 import (
 	"encoding/xml"
+	"fmt"
 	"strings"
 )
 
@@ -29,7 +30,7 @@ type QueryCertificateResponse struct {
 	CertificateIssuer       *string              `json:"certificate-issuer" xml:"certificate-issuer"`
 	CertificateSerial       *string              `json:"certificate-serial" xml:"certificate-serial"`
 	RelatedDomains          []*CertificateDomain `json:"related-domains" xml:"related-domains>related-domain"`
-	DnsNames                []*string            `json:"dns-names" xml:"dns-names"`
+	DnsNames                []*string            `json:"dns-names" xml:"dns-names>dns-name"`
 }
 
 func (c *Client) QueryCertificate(certificateId string) (response QueryCertificateResponse, err error) {
@@ -79,7 +80,26 @@ type UpdateCertificateV2Request struct {
 	Comment     *string `json:"comment,omitempty"`
 }
 
-type updateCertificateRequest struct {
+type UpdateCertificateResponse struct {
+	Code    *string `json:"code" xml:"code"`
+	Message *string `json:"message" xml:"message"`
+}
+
+func (c *Client) UpdateCertificateV2(certificateId string, request UpdateCertificateV2Request) (response UpdateCertificateResponse, err error) {
+	_, err = c.DoXmlApiRequest(Request{
+		Method: HttpPut,
+		Path:   "/api/certificate/" + certificateId,
+		Body:   request,
+	}, &response)
+	return
+}
+
+// AddCertificateService 新增证书
+
+// AddCertificateRequest is also used to update the certificate. If CsrId is
+// set, the certificate is signed from the CSR generated by vendor, and the
+// private key is not required.
+type AddCertificateRequest struct {
 	XMLName             xml.Name `json:"-" xml:"ssl-certificate"`
 	CsrId               *string  `json:"csr-id,omitempty" xml:"csr-id,omitempty"`
 	Name                *string  `json:"name,omitempty" xml:"name,omitempty"`
@@ -90,15 +110,32 @@ type updateCertificateRequest struct {
 	SslKey              *string  `json:"ssl-key,omitempty" xml:"ssl-key,omitempty"`
 }
 
-type UpdateCertificateResponse struct {
-	Code    *string `json:"code" xml:"code"`
-	Message *string `json:"message" xml:"message"`
+type AddCertificateResponse struct {
+	Message       *string `json:"message" xml:"message"`
+	CertificateId *string `json:"-" xml:"-"`
 }
 
-func (c *Client) UpdateCertificateV2(certificateId string, request UpdateCertificateV2Request) (response UpdateCertificateResponse, err error) {
+func (c *Client) AddCertificate(request AddCertificateRequest) (response AddCertificateResponse, err error) {
+	res, err := c.DoXmlApiRequest(Request{
+		Method: HttpPost,
+		Path:   "/api/ssl/certificate",
+		Body:   request,
+	}, &response)
+	if err != nil {
+		return
+	}
+	id, err := idFromLocation(res)
+	if err != nil {
+		return
+	}
+	response.CertificateId = &id
+	return
+}
+
+func (c *Client) UpdateCertificate(certificateId string, request AddCertificateRequest) (response UpdateCertificateResponse, err error) {
 	_, err = c.DoXmlApiRequest(Request{
 		Method: HttpPut,
-		Path:   "/api/certificate/" + certificateId,
+		Path:   "/api/ssl/certificate/" + certificateId,
 		Body:   request,
 	}, &response)
 	return
@@ -142,7 +179,7 @@ type QueryCertificateInfoResponseData struct {
 	Serial                  *string  `json:"serial,omitempty" xml:"serial,omitempty"`
 	NotBefore               *string  `json:"notBefore,omitempty" xml:"notBefore,omitempty"`
 	NotAfter                *string  `json:"notAfter,omitempty" xml:"notAfter,omitempty"`
-	CommonName              *string  `json:"commonName,omitempty" xml:"commenName,omitempty"`
+	CommonName              *string  `json:"commonName,omitempty" xml:"commonName,omitempty"`
 	SubjectAlternativeNames []string `json:"subjectAlternativeNames" xml:"subjectAlternativeNames"`
 }
 
@@ -174,3 +211,93 @@ func (c *Client) DeleteCertificateV2(certificateId string) (response DeleteCerti
 	}, &response)
 	return
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// Certificate Signing Request
+////////////////////////////////////////////////////////////////////////////////
+
+// AddCsr 新增CSR, the private key is generated and kept by vendor.
+
+type AddCsrRequest struct {
+	XMLName                 xml.Name `json:"-" xml:"csr"`
+	Name                    *string  `json:"name,omitempty" xml:"name,omitempty"`
+	CommonName              *string  `json:"common-name,omitempty" xml:"common-name,omitempty"`
+	Organization            *string  `json:"organization,omitempty" xml:"organization,omitempty"`
+	OrganizationUnit        *string  `json:"organization-unit,omitempty" xml:"organization-unit,omitempty"`
+	Locality                *string  `json:"locality,omitempty" xml:"locality,omitempty"`
+	State                   *string  `json:"state,omitempty" xml:"state,omitempty"`
+	Country                 *string  `json:"country,omitempty" xml:"country,omitempty"`
+	SubjectAlternativeNames []string `json:"subject-alternative-names,omitempty" xml:"subject-alternative-names>subject-alternative-name,omitempty"`
+	Algorithm               *string  `json:"algorithm,omitempty" xml:"algorithm,omitempty"`
+}
+
+type AddCsrResponse struct {
+	Message *string `json:"message" xml:"message"`
+	CsrId   *string `json:"-" xml:"-"`
+}
+
+func (c *Client) AddCsr(request AddCsrRequest) (response AddCsrResponse, err error) {
+	res, err := c.DoXmlApiRequest(Request{
+		Method: HttpPost,
+		Path:   "/api/ssl/csr",
+		Body:   request,
+	}, &response)
+	if err != nil {
+		return
+	}
+	id, err := idFromLocation(res)
+	if err != nil {
+		return
+	}
+	response.CsrId = &id
+	return
+}
+
+// idFromLocation returns the ID of the created resource, which is the last
+// segment of the Location header.
+func idFromLocation(res *Response) (string, error) {
+	location := res.Header.Get("Location")
+	id := location[strings.LastIndex(location, "/")+1:]
+	if id == "" {
+		return "", fmt.Errorf("url: %s, no resource ID is found in the Location header %q", res.Url, location)
+	}
+	return id, nil
+}
+
+// QueryCsr 查看CSR
+
+type QueryCsrResponse struct {
+	CsrId                   *string  `json:"csr-id" xml:"csr-id"`
+	Name                    *string  `json:"name" xml:"name"`
+	CommonName              *string  `json:"common-name" xml:"common-name"`
+	Organization            *string  `json:"organization" xml:"organization"`
+	OrganizationUnit        *string  `json:"organization-unit" xml:"organization-unit"`
+	Locality                *string  `json:"locality" xml:"locality"`
+	State                   *string  `json:"state" xml:"state"`
+	Country                 *string  `json:"country" xml:"country"`
+	SubjectAlternativeNames []string `json:"subject-alternative-names" xml:"subject-alternative-names>subject-alternative-name"`
+	Algorithm               *string  `json:"algorithm" xml:"algorithm"`
+	Csr                     *string  `json:"csr" xml:"csr"`
+}
+
+func (c *Client) QueryCsr(csrId string) (response QueryCsrResponse, err error) {
+	_, err = c.DoXmlApiRequest(Request{
+		Method: HttpGet,
+		Path:   "/api/ssl/csr/" + csrId,
+	}, &response)
+	return
+}
+
+// DeleteCsr 删除CSR
+
+type DeleteCsrResponse struct {
+	Message *string `json:"message" xml:"message"`
+}
+
+func (c *Client) DeleteCsr(csrId string) (response DeleteCsrResponse, err error) {
+	_, err = c.DoXmlApiRequest(Request{
+		Method: HttpDelete,
+		Path:   "/api/ssl/csr/" + csrId,
+	}, &response)
+	return
+}
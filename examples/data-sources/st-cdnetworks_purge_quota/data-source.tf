@@ -0,0 +1,2 @@
+data "st-cdnetworks_purge_quota" "quota" {
+}
@@ -0,0 +1,3 @@
+data "st-cdnetworks_purge_status" "test" {
+  task_id = st-cdnetworks_purge.test.task_id
+}
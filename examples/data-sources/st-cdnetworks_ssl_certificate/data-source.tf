@@ -2,3 +2,8 @@ data "st-cdnetworks_ssl_certificate" "certlist" {
 }
 
 
+
+data "st-cdnetworks_ssl_certificate" "expiring" {
+  dns_name            = "www.example.com"
+  expires_within_days = 30
+}
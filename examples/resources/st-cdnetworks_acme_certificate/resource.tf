@@ -0,0 +1,23 @@
+resource "st-cdnetworks_acme_certificate" "test" {
+  name                      = "test"
+  directory_url             = "https://acme-v02.api.letsencrypt.org/directory"
+  email                     = "admin@example.com"
+  common_name               = "www.example.com"
+  subject_alternative_names = ["*.example.com"]
+  key_type                  = "ECDSA256"
+  renew_before_days         = 30
+
+  # The scripts create and delete the TXT record through the DNS provider.
+  challenge = {
+    type                     = "dns-01"
+    present_command          = ["./dns-hook.sh", "present"]
+    cleanup_command          = ["./dns-hook.sh", "cleanup"]
+    propagation_wait_seconds = 60
+  }
+}
+
+resource "st-cdnetworks_domain_ssl_association" "test" {
+  domain_id          = "12345"
+  use_ssl            = true
+  ssl_certificate_id = st-cdnetworks_acme_certificate.test.certificate_id
+}
@@ -0,0 +1,8 @@
+resource "st-cdnetworks_cache_key_config" "test" {
+  domain_id = st-cdnetworks_shield_domain.test.domain_id
+
+  cache_key_rule {
+    path_pattern = ".*\\.html"
+    header_name  = "Accept-Language"
+  }
+}
@@ -5,4 +5,8 @@ resource "st-cdnetworks_cache_time" "test" {
     directory = "/abc/"
     cache_ttl = 100
   }
+
+  purge_on_change {
+    paths = ["/abc/"]
+  }
 }
@@ -0,0 +1,20 @@
+resource "st-cdnetworks_certificate_signing_request" "test" {
+  name                      = "test"
+  common_name               = "www.example.com"
+  organization              = "Example Inc."
+  country                   = "US"
+  subject_alternative_names = ["example.com", "*.example.com"]
+  algorithm                 = "ECDSA256"
+}
+
+# Submit the CSR to a CA, then upload the signed certificate.
+resource "st-cdnetworks_ssl_certificate" "test" {
+  name              = "test"
+  ssl_certificate   = file("signed.pem")
+  certificate_chain = file("chain.pem")
+  csr_id            = st-cdnetworks_certificate_signing_request.test.csr_id
+}
+
+output "csr_pem" {
+  value = st-cdnetworks_certificate_signing_request.test.csr_pem
+}
@@ -7,10 +7,17 @@ resource "st-cdnetworks_content_acceleration_domain" "test" {
   origin_config = {
     origin_ips                 = ["2.2.3.2", "2.2.3.1"]
     default_origin_host_header = "b.abc.com"
+    origin_port                = 8080
   }
   contract_id   = ""
   item_id       = ""
   control_group = {}
+
+  timeouts {
+    create = "30m"
+    update = "30m"
+    delete = "30m"
+  }
 }
 
 
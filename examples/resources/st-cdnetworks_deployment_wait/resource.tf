@@ -0,0 +1,24 @@
+resource "st-cdnetworks_video_drag_config" "test" {
+  domain_id           = st-cdnetworks_content_acceleration_domain.test.domain_id
+  wait_for_deployment = false
+
+  path_pattern = ".*\\.(mp4|flv)"
+  drag_mode    = "byTime"
+}
+
+resource "st-cdnetworks_range_and_follow_config" "test" {
+  domain_id           = st-cdnetworks_content_acceleration_domain.test.domain_id
+  wait_for_deployment = false
+
+  use_range  = true
+  follow_301 = true
+}
+
+resource "st-cdnetworks_deployment_wait" "test" {
+  domain_ids = [st-cdnetworks_content_acceleration_domain.test.domain_id]
+
+  triggers = {
+    video_drag_config       = sha1(jsonencode(st-cdnetworks_video_drag_config.test))
+    range_and_follow_config = sha1(jsonencode(st-cdnetworks_range_and_follow_config.test))
+  }
+}
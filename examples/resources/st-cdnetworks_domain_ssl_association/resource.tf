@@ -1,5 +1,7 @@
 resource "st-cdnetworks_domain_ssl_association" "test" {
-  domain_id          = st-cdnetworks_shield_domain.test.domain_id
-  use_ssl            = true
-  ssl_certificate_id = st-cdnetworks_ssl_certificate.test.ssl_certificate_id
+  domain_id              = st-cdnetworks_shield_domain.test.domain_id
+  use_ssl                = true
+  use_for_sni            = true
+  ssl_certificate_id     = st-cdnetworks_ssl_certificate.rsa.ssl_certificate_id
+  ecc_ssl_certificate_id = st-cdnetworks_ssl_certificate.ecdsa.ssl_certificate_id
 }
@@ -0,0 +1,11 @@
+resource "st-cdnetworks_origin_failover_config" "test" {
+  domain_id = st-cdnetworks_shield_domain.test.domain_id
+
+  detect_url    = "http://origin.example.com/health"
+  detect_period = 30
+
+  adv_origin_config {
+    master_ips = ["2.2.3.1", "2.2.3.2"]
+    backup_ips = ["2.2.4.1"]
+  }
+}
@@ -0,0 +1,10 @@
+resource "st-cdnetworks_prefetch" "test" {
+  urls = [
+    "http://www.example.com/index.html",
+    "http://www.example.com/app.js",
+  ]
+
+  triggers = {
+    release = "v1.0.0"
+  }
+}
@@ -0,0 +1,14 @@
+resource "st-cdnetworks_purge" "test" {
+  urls = [
+    "http://www.example.com/index.html",
+  ]
+  dirs = [
+    "http://www.example.com/images/",
+  ]
+
+  triggers = {
+    release = "v1.0.0"
+  }
+
+  wait_for_completion = true
+}
@@ -0,0 +1,7 @@
+resource "st-cdnetworks_range_and_follow_config" "test" {
+  domain_id = st-cdnetworks_shield_domain.test.domain_id
+
+  use_range  = true
+  follow_301 = true
+  follow_302 = false
+}
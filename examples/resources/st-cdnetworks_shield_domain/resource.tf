@@ -8,6 +8,10 @@ resource "st-cdnetworks_shield_domain" "test" {
     origin_ips                 = ["2.2.3.2", "2.2.3.1"]
     default_origin_host_header = "b.abc.com"
   }
-}
-
+  contract_id   = ""
+  item_id       = ""
+  control_group = {}
 
+  shield_region = "overseas"
+  shield_nodes  = ["hk", "sg"]
+}
@@ -1,6 +1,11 @@
 resource "st-cdnetworks_ssl_certificate" "test" {
-  name            = "test"
-  ssl_certificate = file("cert.pem")
-  ssl_key         = file("key.pem")
+  name              = "test"
+  ssl_certificate   = file("cert.pem")
+  certificate_chain = file("chain.pem")
+  ssl_key           = file("key.pem")
+  update_mode       = "rotate"
 }
 
+output "certificate_not_after" {
+  value = st-cdnetworks_ssl_certificate.test.not_after
+}
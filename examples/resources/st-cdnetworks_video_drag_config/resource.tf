@@ -0,0 +1,8 @@
+resource "st-cdnetworks_video_drag_config" "test" {
+  domain_id = st-cdnetworks_shield_domain.test.domain_id
+
+  path_pattern = ".*\\.(mp4|flv)"
+  drag_mode    = "byTime"
+  start_flag   = "start"
+  end_flag     = "end"
+}